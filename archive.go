@@ -0,0 +1,126 @@
+package goutubedl
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ArchiveStore stores keys of already downloaded media, see Info.ArchiveID.
+// Same idea as youtube-dl --download-archive. Implementations should be safe
+// for concurrent use.
+type ArchiveStore interface {
+	Has(key string) (bool, error)
+	Add(key string) error
+}
+
+// MemoryArchive in-memory ArchiveStore
+type MemoryArchive struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+// NewMemoryArchive returns a new empty MemoryArchive
+func NewMemoryArchive() *MemoryArchive {
+	return &MemoryArchive{keys: map[string]bool{}}
+}
+
+// Has returns true if key is in archive
+func (a *MemoryArchive) Has(key string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.keys[key], nil
+}
+
+// Add key to archive
+func (a *MemoryArchive) Add(key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys[key] = true
+	return nil
+}
+
+// FileArchive ArchiveStore using a file in youtube-dl --download-archive
+// format, one key per line. The file can be shared with youtube-dl.
+type FileArchive struct {
+	mu   sync.Mutex
+	path string
+	keys map[string]bool
+}
+
+// OpenFileArchive reads archive file at path. The file is created on first
+// Add if it does not exist.
+func OpenFileArchive(path string) (*FileArchive, error) {
+	a := &FileArchive{path: path, keys: map[string]bool{}}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if key := strings.TrimSpace(s.Text()); key != "" {
+			a.keys[key] = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Has returns true if key is in archive
+func (a *FileArchive) Has(key string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.keys[key], nil
+}
+
+// Add key to archive and append it to the archive file
+func (a *FileArchive) Add(key string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.keys[key] {
+		return nil
+	}
+
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(key + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	a.keys[key] = true
+
+	return nil
+}
+
+// archived returns true if entry is in options archive
+func (options DownloadOptions) archived(entry Info) (bool, error) {
+	key := entry.ArchiveID()
+	if options.Archive == nil || key == "" {
+		return false, nil
+	}
+	return options.Archive.Has(key)
+}
+
+// archiveAdd adds entry to options archive if any
+func (options DownloadOptions) archiveAdd(entry Info) error {
+	key := entry.ArchiveID()
+	if options.Archive == nil || key == "" {
+		return nil
+	}
+	return options.Archive.Add(key)
+}
@@ -0,0 +1,61 @@
+package goutubedl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func testArchiveStore(t *testing.T, a goutubedl.ArchiveStore) {
+	if ok, err := a.Has("youtube jgVhBThJdXc"); err != nil || ok {
+		t.Fatalf("expected key to not be in archive: %v %v", ok, err)
+	}
+	if err := a.Add("youtube jgVhBThJdXc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Add("youtube jgVhBThJdXc"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := a.Has("youtube jgVhBThJdXc"); err != nil || !ok {
+		t.Fatalf("expected key to be in archive: %v %v", ok, err)
+	}
+}
+
+func TestMemoryArchive(t *testing.T) {
+	testArchiveStore(t, goutubedl.NewMemoryArchive())
+}
+
+func TestFileArchive(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "archive.txt")
+	if err := os.WriteFile(archivePath, []byte("soundcloud 123\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := goutubedl.OpenFileArchive(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := a.Has("soundcloud 123"); err != nil || !ok {
+		t.Fatalf("expected existing key to be in archive: %v %v", ok, err)
+	}
+	testArchiveStore(t, a)
+
+	b, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "soundcloud 123\nyoutube jgVhBThJdXc\n"
+	if string(b) != expected {
+		t.Errorf("expected archive file %q got %q", expected, string(b))
+	}
+
+	a, err = goutubedl.OpenFileArchive(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := a.Has("youtube jgVhBThJdXc"); err != nil || !ok {
+		t.Fatalf("expected reopened archive to have key: %v %v", ok, err)
+	}
+}
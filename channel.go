@@ -0,0 +1,194 @@
+package goutubedl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ChannelTab tab of a channel, the last path segment of a tab URL
+type ChannelTab string
+
+// Known channel tabs, same names as used in YouTube channel URLs
+const (
+	ChannelTabVideos    ChannelTab = "videos"
+	ChannelTabShorts    ChannelTab = "shorts"
+	ChannelTabStreams   ChannelTab = "streams"
+	ChannelTabPlaylists ChannelTab = "playlists"
+	ChannelTabPodcasts  ChannelTab = "podcasts"
+	ChannelTabReleases  ChannelTab = "releases"
+)
+
+var knownChannelTabs = map[ChannelTab]bool{
+	ChannelTabVideos:    true,
+	ChannelTabShorts:    true,
+	ChannelTabStreams:   true,
+	ChannelTabPlaylists: true,
+	ChannelTabPodcasts:  true,
+	ChannelTabReleases:  true,
+	"featured":          true,
+	"community":         true,
+	"live":              true,
+}
+
+// Channel channel metadata, see Result.Channel
+type Channel struct {
+	ID            string
+	Name          string
+	URL           string
+	Description   string
+	FollowerCount float64
+	Avatar        Thumbnail // zero if not known
+	Banner        Thumbnail // zero if not known
+	// Tabs of the entries in order of first appearance
+	Tabs []ChannelTab
+}
+
+// channelTabFromURL returns tab for a channel tab URL or empty if not a known tab
+func channelTabFromURL(rawURL string) ChannelTab {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	tab := ChannelTab(path.Base(u.Path))
+	if !knownChannelTabs[tab] {
+		return ""
+	}
+	return tab
+}
+
+// channelBaseURL returns channel URL without tab and query
+func channelBaseURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if knownChannelTabs[ChannelTab(path.Base(u.Path))] {
+		u.Path = path.Dir(u.Path)
+	}
+	u.RawPath = ""
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// channelTabsInfo fetches each tab in options.ChannelTabs and returns info
+// and raw JSON for the channel with the tabs as nested playlists, same shape
+// as youtube-dl returns for a channel URL without tab.
+// Tabs the channel does not have are skipped.
+func channelTabsInfo(ctx context.Context, rawURL string, options Options) (Info, []byte, error) {
+	baseURL, err := channelBaseURL(rawURL)
+	if err != nil {
+		return Info{}, nil, err
+	}
+
+	tabOptions := options
+	tabOptions.ChannelTabs = nil
+	tabOptions.Type = TypePlaylist
+
+	var channelRaw map[string]json.RawMessage
+	var tabsRaw []json.RawMessage
+	var lastErr error
+	for _, tab := range options.ChannelTabs {
+		tabURL := baseURL + "/" + string(tab)
+		_, raw, err := infoFromURL(ctx, tabURL, tabOptions)
+		if err != nil {
+			var ytErr YoutubedlError
+			if !errors.As(err, &ytErr) {
+				return Info{}, nil, err
+			}
+			options.DebugLog.Print("channel tab ", tabURL, ": ", err)
+			lastErr = err
+			continue
+		}
+		if channelRaw == nil {
+			if err := json.Unmarshal(raw, &channelRaw); err != nil {
+				return Info{}, nil, err
+			}
+		}
+		tabsRaw = append(tabsRaw, raw)
+	}
+	if channelRaw == nil {
+		if lastErr == nil {
+			lastErr = errors.New("no channel tabs")
+		}
+		return Info{}, nil, lastErr
+	}
+
+	// channel fields are from first tab
+	for k, v := range map[string]interface{}{
+		"entries":        tabsRaw,
+		"webpage_url":    baseURL,
+		"playlist_count": len(tabsRaw),
+	} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return Info{}, nil, err
+		}
+		channelRaw[k] = b
+	}
+	if id, ok := channelRaw["channel_id"]; ok {
+		channelRaw["id"] = id
+	}
+	rawJSON, err := json.Marshal(channelRaw)
+	if err != nil {
+		return Info{}, nil, err
+	}
+
+	var info Info
+	if err := json.Unmarshal(rawJSON, &info); err != nil {
+		return Info{}, nil, err
+	}
+	info.Entries = flattenEntries(info.Entries)
+	splitStoryboards(&info, options.IncludeStoryboards)
+	info.Entries = options.MatchFilter.filterEntries(info.Entries)
+
+	return info, rawJSON, nil
+}
+
+// Channel returns channel metadata. For a channel result it's the channel
+// itself, for a single entry it's the channel the entry was uploaded on.
+func (result Result) Channel() Channel {
+	info := result.Info
+	c := Channel{
+		ID:            info.ChannelID,
+		Name:          info.Channel,
+		URL:           info.ChannelURL,
+		FollowerCount: info.ChannelFollowerCount,
+	}
+	if c.Name == "" {
+		c.Name = info.Uploader
+	}
+
+	isChannel := info.ChannelID != "" && info.ID == info.ChannelID
+	if !isChannel {
+		return c
+	}
+
+	c.Description = info.Description
+	if c.URL == "" {
+		c.URL = info.WebpageURL
+	}
+	for _, t := range info.Thumbnails {
+		switch {
+		case t.ID == "avatar_uncropped" || (c.Avatar.URL == "" && strings.Contains(t.ID, "avatar")):
+			c.Avatar = t
+		case t.ID == "banner_uncropped" || (c.Banner.URL == "" && strings.Contains(t.ID, "banner")):
+			c.Banner = t
+		}
+	}
+	seenTabs := map[ChannelTab]bool{}
+	for _, e := range info.Entries {
+		if e.ChannelTab == "" || seenTabs[e.ChannelTab] {
+			continue
+		}
+		seenTabs[e.ChannelTab] = true
+		c.Tabs = append(c.Tabs, e.ChannelTab)
+	}
+
+	return c
+}
@@ -0,0 +1,111 @@
+package goutubedl_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestChannelFake(t *testing.T) {
+	useFake(t)
+
+	for _, c := range []struct {
+		name         string
+		url          string
+		tabs         []goutubedl.ChannelTab
+		expectedIDs  []string
+		expectedTabs []goutubedl.ChannelTab
+	}{
+		{"default", fakeChannelURL, nil, []string{"v3", "v2", "v1", "s2", "s1"}, []goutubedl.ChannelTab{"videos", "shorts"}},
+		{"shorts", fakeChannelURL, []goutubedl.ChannelTab{goutubedl.ChannelTabShorts}, []string{"s2", "s1"}, []goutubedl.ChannelTab{"shorts"}},
+		{
+			"missing_tab",
+			fakeChannelURL,
+			[]goutubedl.ChannelTab{goutubedl.ChannelTabStreams, goutubedl.ChannelTabVideos},
+			[]string{"v3", "v2", "v1"},
+			[]goutubedl.ChannelTab{"videos"},
+		},
+		{"single_tab_url", fakeChannelURL + "/videos", nil, []string{"v3", "v2", "v1"}, []goutubedl.ChannelTab{"videos"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := goutubedl.New(context.Background(), c.url, goutubedl.Options{
+				Type:         goutubedl.TypeChannel,
+				FlatPlaylist: true,
+				ChannelTabs:  c.tabs,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, e := range result.Info.Entries {
+				ids = append(ids, e.ID)
+				expectedTab := goutubedl.ChannelTabVideos
+				if strings.HasPrefix(e.ID, "s") {
+					expectedTab = goutubedl.ChannelTabShorts
+				}
+				if e.ChannelTab != expectedTab {
+					t.Errorf("expected %s to have tab %s got %s", e.ID, expectedTab, e.ChannelTab)
+				}
+			}
+			if !reflect.DeepEqual(c.expectedIDs, ids) {
+				t.Errorf("expected entries %v got %v", c.expectedIDs, ids)
+			}
+
+			ch := result.Channel()
+			if ch.ID != "channel" || ch.Name != "Channel" || ch.URL != fakeChannelURL ||
+				ch.FollowerCount != 123 || ch.Description != "Channel description" ||
+				ch.Avatar.URL != "fake://avatar.jpg" || ch.Banner.URL != "fake://banner.jpg" {
+				t.Errorf("unexpected channel %#v", ch)
+			}
+			if !reflect.DeepEqual(c.expectedTabs, ch.Tabs) {
+				t.Errorf("expected tabs %v got %v", c.expectedTabs, ch.Tabs)
+			}
+
+			// entries from tabs can be downloaded using the combined info
+			last := result.Info.Entries[len(result.Info.Entries)-1]
+			dr, err := result.DownloadEntry(context.Background(), last.ID, goutubedl.DownloadOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			downloaded, err := readFakeDownload(t, dr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if downloaded.ID != last.ID {
+				t.Errorf("expected download of %s got %s", last.ID, downloaded.ID)
+			}
+		})
+	}
+}
+
+func TestEntryNestedFake(t *testing.T) {
+	useFake(t)
+
+	// without TypeChannel tabs are kept as nested playlists
+	result, err := goutubedl.New(context.Background(), fakeChannelURL, goutubedl.Options{FlatPlaylist: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := result.Entry("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.ID != "s1" {
+		t.Errorf("expected s1 got %s", entry.ID)
+	}
+	dr, err := result.DownloadEntry(context.Background(), "s1", goutubedl.DownloadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloaded, err := readFakeDownload(t, dr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downloaded.ID != "s1" {
+		t.Errorf("expected download of s1 got %s", downloaded.ID)
+	}
+}
@@ -0,0 +1,100 @@
+package goutubedl
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CommentSort comment sort order
+type CommentSort string
+
+// Comment sort orders
+const (
+	CommentSortTop CommentSort = "top"
+	CommentSortNew CommentSort = "new"
+)
+
+// CommentOptions options for extracting comments. Limits and sort order are
+// passed as youtube extractor arguments, zero limit is no limit.
+type CommentOptions struct {
+	MaxComments         int // Max total number of comments
+	MaxParents          int // Max number of top-level comments
+	MaxReplies          int // Max total number of replies
+	MaxRepliesPerThread int // Max number of replies per top-level comment
+	Sort                CommentSort
+}
+
+func (o CommentOptions) args() []string {
+	args := []string{"--write-comments"}
+
+	var extractorArgs []string
+	if o.MaxComments > 0 || o.MaxParents > 0 || o.MaxReplies > 0 || o.MaxRepliesPerThread > 0 {
+		var maxes []string
+		for _, n := range []int{o.MaxComments, o.MaxParents, o.MaxReplies, o.MaxRepliesPerThread} {
+			if n > 0 {
+				maxes = append(maxes, strconv.Itoa(n))
+			} else {
+				maxes = append(maxes, "all")
+			}
+		}
+		extractorArgs = append(extractorArgs, "max_comments="+strings.Join(maxes, ","))
+	}
+	if o.Sort != "" {
+		extractorArgs = append(extractorArgs, "comment_sort="+string(o.Sort))
+	}
+	if len(extractorArgs) > 0 {
+		args = append(args, "--extractor-args", "youtube:"+strings.Join(extractorArgs, ";"))
+	}
+
+	return args
+}
+
+// commentRootParent parent of top-level comments
+const commentRootParent = "root"
+
+// Comment youtube-dl comment, requested with Options.Comments
+type Comment struct {
+	ID               string  `json:"id"`
+	Parent           string  `json:"parent"` // Parent comment ID or "root" for top-level comments
+	Text             string  `json:"text"`
+	Author           string  `json:"author"`
+	AuthorID         string  `json:"author_id"`
+	AuthorThumbnail  string  `json:"author_thumbnail"`
+	AuthorURL        string  `json:"author_url"`
+	AuthorIsUploader bool    `json:"author_is_uploader"`
+	AuthorIsVerified bool    `json:"author_is_verified"`
+	LikeCount        float64 `json:"like_count"`
+	Timestamp        float64 `json:"timestamp"` // UNIX timestamp, might be approximate
+	IsPinned         bool    `json:"is_pinned"`
+	IsFavorited      bool    `json:"is_favorited"` // Hearted by the uploader
+}
+
+// CommentThread comment with its replies
+type CommentThread struct {
+	Comment
+	Replies []*CommentThread
+}
+
+// CommentTree returns top-level comments with replies built from parent IDs.
+// Order of comments is preserved. Replies to unknown comments are returned
+// as top-level comments.
+func (info Info) CommentTree() []*CommentThread {
+	threads := make([]*CommentThread, len(info.Comments))
+	byID := map[string]*CommentThread{}
+	for i, c := range info.Comments {
+		threads[i] = &CommentThread{Comment: c}
+		byID[c.ID] = threads[i]
+	}
+
+	var roots []*CommentThread
+	for _, t := range threads {
+		parent, ok := byID[t.Parent]
+		if t.Parent == "" || t.Parent == commentRootParent || !ok || parent == t {
+			roots = append(roots, t)
+			continue
+		}
+		parent.Replies = append(parent.Replies, t)
+	}
+
+	return roots
+}
@@ -0,0 +1,89 @@
+package goutubedl_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func commentTreeIDs(threads []*goutubedl.CommentThread) []string {
+	var ids []string
+	for _, t := range threads {
+		id := t.ID
+		if len(t.Replies) > 0 {
+			id += "(" + strings.Join(commentTreeIDs(t.Replies), " ") + ")"
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestCommentsFake(t *testing.T) {
+	useFake(t)
+
+	for _, c := range []struct {
+		name         string
+		options      goutubedl.CommentOptions
+		expectedArgs string
+	}{
+		{"default", goutubedl.CommentOptions{}, "--write-comments"},
+		{
+			"options",
+			goutubedl.CommentOptions{MaxComments: 100, MaxRepliesPerThread: 5, Sort: goutubedl.CommentSortNew},
+			"--write-comments --extractor-args youtube:max_comments=100,all,all,5;comment_sort=new",
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{
+				Comments:       true,
+				CommentOptions: c.options,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			args := strings.Join(fakeArgs(t, result), " ")
+			if !strings.Contains(args, c.expectedArgs) || strings.Contains(args, "--extractor-args") != strings.Contains(c.expectedArgs, "--extractor-args") {
+				t.Errorf("expected %q in args %q", c.expectedArgs, args)
+			}
+
+			first := result.Info.Comments[0]
+			if first.Text != "first" || first.LikeCount != 10 || !first.IsPinned {
+				t.Errorf("unexpected first comment %#v", first)
+			}
+			if !result.Info.Comments[1].IsFavorited || !result.Info.Comments[3].AuthorIsUploader {
+				t.Errorf("unexpected comments %#v", result.Info.Comments)
+			}
+
+			expectedTree := []string{"c1(c1.r1 c1.r2)", "c2"}
+			if tree := commentTreeIDs(result.Info.CommentTree()); !reflect.DeepEqual(expectedTree, tree) {
+				t.Errorf("expected %v got %v", expectedTree, tree)
+			}
+		})
+	}
+
+	result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args := strings.Join(fakeArgs(t, result), " "); strings.Contains(args, "--write-comments") {
+		t.Errorf("unexpected --write-comments in args %q", args)
+	}
+}
+
+func TestCommentTree(t *testing.T) {
+	info := goutubedl.Info{Comments: []goutubedl.Comment{
+		{ID: "r1", Parent: "a"},
+		{ID: "a", Parent: "root"},
+		{ID: "orphan", Parent: "missing"},
+		{ID: "r2", Parent: "r1"},
+		{ID: "b"},
+	}}
+	expected := []string{"a(r1(r2))", "orphan", "b"}
+	if tree := commentTreeIDs(info.CommentTree()); !reflect.DeepEqual(expected, tree) {
+		t.Errorf("expected %v got %v", expected, tree)
+	}
+}
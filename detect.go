@@ -0,0 +1,78 @@
+package goutubedl
+
+import (
+	"context"
+)
+
+// Detection result of Detect
+type Detection struct {
+	Type         Type   // TypeSingle, TypePlaylist or TypeChannel
+	Extractor    string // Name of the extractor
+	ExtractorKey string // Key name of the extractor
+	ID           string // Video, playlist or channel identifier
+	WebpageURL   string // Canonical URL
+	Title        string
+	// Number of entries, 1 for single. -1 if unknown for playlists and channels.
+	EntryCount int
+}
+
+// Detect type of URL without full extraction. Playlists and channels are
+// flat extracted and limited to the first entry so this is fast also for
+// large playlists and channels.
+func Detect(ctx context.Context, rawURL string, options Options) (Detection, error) {
+	if options.DebugLog == nil {
+		options.DebugLog = nopPrinter{}
+	}
+	options.Type = TypeAny
+	options.FlatPlaylist = true
+	options.PlaylistStart = 1
+	options.PlaylistEnd = 1
+	options.anyPlaylistArgs = true
+	options.DownloadThumbnail = false
+	options.DownloadSubtitles = false
+	options.Comments = false
+	options.MatchFilter = MatchFilter{}
+
+	info, _, err := infoFromURL(ctx, rawURL, options)
+	if err != nil {
+		return Detection{}, err
+	}
+
+	d := Detection{
+		Type:         TypeSingle,
+		Extractor:    info.Extractor,
+		ExtractorKey: info.ExtractorKey,
+		ID:           info.ID,
+		WebpageURL:   info.WebpageURL,
+		Title:        info.Title,
+		EntryCount:   1,
+	}
+
+	if info.Type == "playlist" || info.Type == "multi_video" {
+		d.Type = TypePlaylist
+		// channels are playlists of the channel id or have tabs as nested playlists
+		if (info.ChannelID != "" && info.ID == info.ChannelID) ||
+			(len(info.Entries) > 0 && info.Entries[0].Type == "playlist") {
+			d.Type = TypeChannel
+		}
+		d.EntryCount = -1
+		if info.PlaylistCount > 0 {
+			d.EntryCount = int(info.PlaylistCount)
+		}
+	}
+
+	return d, nil
+}
+
+// Expect returns ErrNotAPlaylist or ErrNotASingleEntry if detected type does
+// not match t, same errors as New would return for Options.Type t.
+func (d Detection) Expect(t Type) error {
+	isPlaylist := d.Type == TypePlaylist || d.Type == TypeChannel
+	switch {
+	case (t == TypePlaylist || t == TypeChannel) && !isPlaylist:
+		return ErrNotAPlaylist
+	case t == TypeSingle && isPlaylist:
+		return ErrNotASingleEntry
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package goutubedl_test
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestDetectFake(t *testing.T) {
+	useFake(t)
+
+	for _, c := range []struct {
+		url           string
+		expectedType  goutubedl.Type
+		expectedCount int
+	}{
+		{fakeSingleURL, goutubedl.TypeSingle, 1},
+		{fakePlaylistURL, goutubedl.TypePlaylist, 5},
+		{fakeChannelURL, goutubedl.TypeChannel, -1},
+	} {
+		t.Run(c.url, func(t *testing.T) {
+			d, err := goutubedl.Detect(context.Background(), c.url, goutubedl.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Type != c.expectedType || d.EntryCount != c.expectedCount {
+				t.Errorf("expected %v %d got %v %d", c.expectedType, c.expectedCount, d.Type, d.EntryCount)
+			}
+		})
+	}
+}
+
+func TestDetectIgnoresEntryOptionsFake(t *testing.T) {
+	useFake(t)
+
+	// filter and comments options are for New and should not affect detection
+	logBuf := &syncBuffer{}
+	d, err := goutubedl.Detect(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Comments: true,
+		MatchFilter: goutubedl.MatchFilter{
+			Match: []string{"!is_live"},
+			Func:  func(info goutubedl.Info) bool { return false },
+		},
+		DebugLog: log.New(logBuf, "", 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Type != goutubedl.TypePlaylist || d.EntryCount != 5 {
+		t.Errorf("expected %v 5 got %v %d", goutubedl.TypePlaylist, d.Type, d.EntryCount)
+	}
+	for _, arg := range []string{"--write-comments", "--match-filters"} {
+		if strings.Contains(logBuf.String(), arg) {
+			t.Errorf("expected no %s in %q", arg, logBuf.String())
+		}
+	}
+}
+
+func TestTypeAnyIgnoresPlaylistOptionsFake(t *testing.T) {
+	useFake(t)
+
+	result, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		PlaylistEnd:  2,
+		FlatPlaylist: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Info.Entries) != 5 {
+		t.Errorf("expected 5 entries got %d", len(result.Info.Entries))
+	}
+	for _, e := range result.Info.Entries {
+		if e.IsFlat() {
+			t.Errorf("expected %s to not be flat", e.ID)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package goutubedl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DownloadAllOptions options for DownloadAll
+type DownloadAllOptions struct {
+	DownloadOptions
+	// Number of retries per entry if download fails or Fn returns an error
+	Retries int
+	// Delay before first retry, doubled for each following retry (default 1s)
+	RetryDelay time.Duration
+	// Fn is called with each entry download and the download is closed after Fn returns.
+	// Fn is called concurrently from multiple workers.
+	Fn func(info Info, dr *DownloadResult) error
+	// If not nil and returns true the entry is skipped
+	Skip func(info Info) bool
+}
+
+// EntryError error for a playlist entry
+type EntryError struct {
+	Info Info
+	Err  error
+}
+
+func (e EntryError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Info.ID, e.Err)
+}
+
+func (e EntryError) Unwrap() error {
+	return e.Err
+}
+
+// DownloadAllSummary summary of DownloadAll, entries are in selection order.
+// Entries not started because of cancel or BreakOnExisting are not included.
+type DownloadAllSummary struct {
+	Succeeded []Info
+	Skipped   []Info
+	Failed    []EntryError
+}
+
+type entryStatus int
+
+const (
+	entryPending entryStatus = iota
+	entrySucceeded
+	entrySkipped
+	entryFailed
+)
+
+// DownloadAll downloads playlist entries selected by options using concurrency
+// number of workers. Each entry download is passed to options.Fn.
+// Entries in options.Archive are skipped and entries are added to it if Fn read
+// the download to end and youtube-dl exited successfully.
+// If ctx is cancelled no more entries are started and ctx error is returned
+// together with summary of the entries processed so far.
+func (result Result) DownloadAll(
+	ctx context.Context,
+	options DownloadAllOptions,
+	concurrency int,
+) (DownloadAllSummary, error) {
+	if result.Options.noInfoDownload {
+		return DownloadAllSummary{}, errors.New("can't download playlist entries without info")
+	}
+	if options.Fn == nil {
+		return DownloadAllSummary{}, errors.New("download all needs a Fn option")
+	}
+	if options.PlaylistIndex != 0 {
+		return DownloadAllSummary{}, errors.New("playlist index option can't be used with download all")
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	retryDelay := options.RetryDelay
+	if retryDelay == 0 {
+		retryDelay = time.Second
+	}
+
+	raw, err := result.rawEntries()
+	if err != nil {
+		return DownloadAllSummary{}, err
+	}
+	entries := result.selectedEntries(options.DownloadOptions)
+
+	dlOptions := options.DownloadOptions
+	dlOptions.PlaylistItems = nil
+	dlOptions.PlaylistReverse = false
+	dlOptions.PlaylistRandom = false
+
+	downloadEntry := func(entry Info) error {
+		var err error
+		delay := retryDelay
+		for attempt := 0; attempt <= options.Retries; attempt++ {
+			if attempt > 0 {
+				result.Options.DebugLog.Print("retry ", entry.ID, " ", attempt, ": ", err)
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				delay *= 2
+			}
+
+			var dr *DownloadResult
+			dr, err = result.downloadEntry(ctx, entry, raw, dlOptions)
+			if dr != nil {
+				dr.onComplete = func() error { return options.archiveAdd(entry) }
+			}
+			if err == nil {
+				err = options.Fn(entry, dr)
+			}
+			if dr != nil {
+				if closeErr := dr.Close(); err == nil {
+					err = closeErr
+				}
+				if err == nil {
+					err = dr.exitErr()
+				}
+			}
+			if err == nil || ctx.Err() != nil {
+				break
+			}
+		}
+		return err
+	}
+
+	statuses := make([]entryStatus, len(entries))
+	errs := make([]error, len(entries))
+	indexCh := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				if options.Skip != nil && options.Skip(entries[i]) {
+					statuses[i] = entrySkipped
+					continue
+				}
+				if err := downloadEntry(entries[i]); err != nil {
+					statuses[i] = entryFailed
+					errs[i] = err
+					continue
+				}
+				statuses[i] = entrySucceeded
+			}
+		}()
+	}
+
+feed:
+	for i := range entries {
+		// check archive here to know where to stop if BreakOnExisting
+		archived, err := options.archived(entries[i])
+		if err != nil {
+			statuses[i] = entryFailed
+			errs[i] = err
+			continue
+		} else if archived {
+			statuses[i] = entrySkipped
+			if options.BreakOnExisting {
+				break
+			}
+			continue
+		}
+
+		select {
+		case indexCh <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexCh)
+	wg.Wait()
+
+	var summary DownloadAllSummary
+	for i, s := range statuses {
+		switch s {
+		case entrySucceeded:
+			summary.Succeeded = append(summary.Succeeded, entries[i])
+		case entrySkipped:
+			summary.Skipped = append(summary.Skipped, entries[i])
+		case entryFailed:
+			summary.Failed = append(summary.Failed, EntryError{Info: entries[i], Err: errs[i]})
+		}
+	}
+
+	return summary, ctx.Err()
+}
@@ -0,0 +1,201 @@
+package goutubedl_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestDownloadCloseFake(t *testing.T) {
+	useFake(t)
+	os.Setenv(fakeFailEnv, "single")
+
+	result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dr, err := result.Download(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// youtube-dl exit error is only reported by DownloadAll
+	if _, err := readFakeDownload(t, dr); err != nil {
+		t.Errorf("expected no error got %s", err)
+	}
+
+	// closing before end is not an error
+	dr, err = result.Download(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dr.Close(); err != nil {
+		t.Errorf("expected no error got %s", err)
+	}
+}
+
+func TestDownloadAllRetryFake(t *testing.T) {
+	useFake(t)
+	os.Setenv(fakeFailEnv, "e2")
+
+	result, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Type: goutubedl.TypePlaylist,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := map[string]int{}
+	summary, err := result.DownloadAll(context.Background(), goutubedl.DownloadAllOptions{
+		Retries:    1,
+		RetryDelay: 1,
+		Fn: func(info goutubedl.Info, dr *goutubedl.DownloadResult) error {
+			attempts[info.ID]++
+			_, err := io.Copy(io.Discard, dr)
+			return err
+		},
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Succeeded) != 4 {
+		t.Errorf("expected 4 succeeded got %d", len(summary.Succeeded))
+	}
+	if len(summary.Failed) != 1 || summary.Failed[0].Info.ID != "e2" {
+		t.Fatalf("expected e2 to fail got %v", summary.Failed)
+	}
+	if attempts["e2"] != 2 {
+		t.Errorf("expected 2 attempts for e2 got %d", attempts["e2"])
+	}
+}
+
+func fakeArchive(t *testing.T, ids ...string) goutubedl.ArchiveStore {
+	t.Helper()
+	a := goutubedl.NewMemoryArchive()
+	for _, id := range ids {
+		if err := a.Add(goutubedl.Info{ID: id, ExtractorKey: "Fake"}.ArchiveID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return a
+}
+
+func archivedIDs(t *testing.T, a goutubedl.ArchiveStore, ids ...string) []string {
+	t.Helper()
+	var archived []string
+	for _, id := range ids {
+		ok, err := a.Has(goutubedl.Info{ID: id, ExtractorKey: "Fake"}.ArchiveID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			archived = append(archived, id)
+		}
+	}
+	return archived
+}
+
+func infoIDs(infos []goutubedl.Info) []string {
+	var ids []string
+	for _, i := range infos {
+		ids = append(ids, i.ID)
+	}
+	return ids
+}
+
+var allFakeIDs = []string{"e5", "e4", "e3", "e2", "e1"}
+
+func TestDownloadArchiveFake(t *testing.T) {
+	useFake(t)
+	// e2 fails after download has started
+	os.Setenv(fakeFailEnv, "e2")
+
+	result, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Type: goutubedl.TypePlaylist,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readAll := func(dr *goutubedl.DownloadResult) error {
+		_, err := io.Copy(io.Discard, dr)
+		return err
+	}
+	readNone := func(dr *goutubedl.DownloadResult) error { return nil }
+
+	for _, c := range []struct {
+		name              string
+		archived          []string
+		breakOnExisting   bool
+		read              func(dr *goutubedl.DownloadResult) error
+		expectedSucceeded []string
+		expectedArchived  []string
+	}{
+		{"skip", []string{"e3"}, false, readAll, []string{"e5", "e4", "e1"}, []string{"e5", "e4", "e3", "e1"}},
+		{"break", []string{"e3"}, true, readAll, []string{"e5", "e4"}, []string{"e5", "e4", "e3"}},
+		{"not_read_to_end", nil, false, readNone, []string{"e5", "e4", "e3", "e2", "e1"}, nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Run("DownloadAll", func(t *testing.T) {
+				archive := fakeArchive(t, c.archived...)
+				summary, err := result.DownloadAll(context.Background(), goutubedl.DownloadAllOptions{
+					DownloadOptions: goutubedl.DownloadOptions{
+						Archive:         archive,
+						BreakOnExisting: c.breakOnExisting,
+					},
+					RetryDelay: 1,
+					Fn: func(info goutubedl.Info, dr *goutubedl.DownloadResult) error {
+						return c.read(dr)
+					},
+				}, 2)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				succeeded := infoIDs(summary.Succeeded)
+				if !reflect.DeepEqual(c.expectedSucceeded, succeeded) {
+					t.Errorf("expected succeeded %v got %v", c.expectedSucceeded, succeeded)
+				}
+				if archived := archivedIDs(t, archive, allFakeIDs...); !reflect.DeepEqual(c.expectedArchived, archived) {
+					t.Errorf("expected archived %v got %v", c.expectedArchived, archived)
+				}
+			})
+
+			t.Run("PlaylistDownload", func(t *testing.T) {
+				archive := fakeArchive(t, c.archived...)
+				pd, err := result.DownloadPlaylist(goutubedl.DownloadOptions{
+					Archive:         archive,
+					BreakOnExisting: c.breakOnExisting,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// Close does not return youtube-dl exit error but failed
+				// entries are not added to the archive
+				for {
+					_, dr, err := pd.Next(context.Background())
+					if err == io.EOF {
+						break
+					} else if err != nil {
+						t.Fatal(err)
+					}
+					if err := c.read(dr); err != nil {
+						t.Fatal(err)
+					}
+					if err := dr.Close(); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if archived := archivedIDs(t, archive, allFakeIDs...); !reflect.DeepEqual(c.expectedArchived, archived) {
+					t.Errorf("expected archived %v got %v", c.expectedArchived, archived)
+				}
+			})
+		})
+	}
+}
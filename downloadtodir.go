@@ -0,0 +1,201 @@
+package goutubedl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultOutputTemplate default DownloadToDir output template
+const DefaultOutputTemplate = "%(title)s [%(id)s].%(ext)s"
+
+// DownloadToDirOptions options for DownloadToDir
+type DownloadToDirOptions struct {
+	DownloadOptions
+	// youtube-dl output template relative to dir (default DefaultOutputTemplate)
+	OutputTemplate string
+	// Write subtitles selected by Options subtitle options (--write-subs)
+	WriteSubtitles bool
+	WriteThumbnail bool // --write-thumbnail
+	WriteInfoJSON  bool // --write-info-json
+}
+
+// FileRole role of a file written by DownloadToDir
+type FileRole string
+
+// File roles
+const (
+	FileRoleVideo     FileRole = "video" // Video or audio file
+	FileRoleSubtitle  FileRole = "subtitle"
+	FileRoleThumbnail FileRole = "thumbnail"
+	FileRoleInfoJSON  FileRole = "info_json"
+)
+
+// DownloadedFile file written by DownloadToDir
+type DownloadedFile struct {
+	Path string // Path including dir
+	Role FileRole
+}
+
+var subtitleExts = map[string]bool{
+	".vtt": true, ".srt": true, ".ttml": true, ".dfxp": true, ".json3": true,
+	".srv1": true, ".srv2": true, ".srv3": true, ".ass": true, ".lrc": true,
+}
+
+var thumbnailExts = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".webp": true,
+}
+
+func fileRole(path string) FileRole {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case strings.HasSuffix(path, ".info.json"):
+		return FileRoleInfoJSON
+	case subtitleExts[ext] || strings.HasSuffix(path, ".live_chat.json"):
+		return FileRoleSubtitle
+	case thumbnailExts[ext]:
+		return FileRoleThumbnail
+	default:
+		return FileRoleVideo
+	}
+}
+
+// DownloadToDir downloads to files in dir named by options.OutputTemplate and
+// returns the files written sorted by path. youtube-dl writes to a temporary
+// directory in dir and files are moved into dir only if it succeeds, an error is
+// returned without moving any file if a file already exists.
+// Use this instead of DownloadWithOptions when post-processing needs real
+// files, ex: merged formats or audio extraction.
+func (result Result) DownloadToDir(
+	ctx context.Context,
+	dir string,
+	options DownloadToDirOptions,
+) ([]DownloadedFile, error) {
+	outputTemplate := options.OutputTemplate
+	if outputTemplate == "" {
+		outputTemplate = DefaultOutputTemplate
+	}
+	if filepath.IsAbs(outputTemplate) ||
+		strings.HasPrefix(filepath.Clean(outputTemplate), "..") {
+		return nil, fmt.Errorf("output template %q must be relative to dir", outputTemplate)
+	}
+
+	info, infoJSON, playlistIndex, err := result.downloadTarget(options.DownloadOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range options.Sections {
+		if err := s.Validate(info.Duration); err != nil {
+			return nil, err
+		}
+	}
+
+	// same filesystem as dir so that files can be renamed into place
+	outputPath, err := os.MkdirTemp(dir, ".goutubedl-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(outputPath)
+
+	tempPath, err := os.MkdirTemp("", "ydls")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempPath)
+
+	var jsonTempPath string
+	if !result.Options.noInfoDownload {
+		jsonTempPath = filepath.Join(tempPath, "info.json")
+		if err := os.WriteFile(jsonTempPath, infoJSON, 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := result.downloadCmd(
+		ctx,
+		info,
+		jsonTempPath,
+		playlistIndex,
+		filepath.Join(outputPath, outputTemplate),
+		options.DownloadOptions,
+	)
+	if options.WriteSubtitles {
+		cmd.Args = append(cmd.Args, "--write-subs")
+		cmd.Args = append(cmd.Args, result.Options.subtitleArgs()...)
+	}
+	if options.WriteThumbnail {
+		cmd.Args = append(cmd.Args, "--write-thumbnail")
+	}
+	if options.WriteInfoJSON {
+		cmd.Args = append(cmd.Args, "--write-info-json")
+	}
+
+	stderrBuf := &bytes.Buffer{}
+	stderrWriter := io.Discard
+	if result.Options.StderrFn != nil {
+		stderrWriter = result.Options.StderrFn(cmd)
+	}
+	cmd.Dir = tempPath
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.MultiWriter(stderrBuf, stderrWriter)
+
+	result.Options.DebugLog.Print("cmd", " ", cmd.Args)
+	if err := cmd.Run(); err != nil {
+		const errorPrefix = "ERROR: "
+		var lastErr string
+		for _, line := range strings.Split(stderrBuf.String(), "\n") {
+			if strings.HasPrefix(line, errorPrefix) {
+				lastErr = strings.TrimSpace(line[len(errorPrefix):])
+			}
+		}
+		if lastErr != "" {
+			return nil, YoutubedlError(lastErr)
+		}
+		return nil, err
+	}
+
+	var rels []string
+	if err := filepath.Walk(outputPath, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(outputPath, path)
+		if err != nil {
+			return err
+		}
+		rels = append(rels, rel)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(rels)
+	if len(rels) == 0 {
+		return nil, errors.New("no files were written")
+	}
+
+	for _, rel := range rels {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err == nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(dir, rel), os.ErrExist)
+		}
+	}
+
+	var files []DownloadedFile
+	for _, rel := range rels {
+		p := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return files, err
+		}
+		if err := os.Rename(filepath.Join(outputPath, rel), p); err != nil {
+			return files, err
+		}
+		files = append(files, DownloadedFile{Path: p, Role: fileRole(p)})
+	}
+
+	return files, nil
+}
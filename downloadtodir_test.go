@@ -0,0 +1,149 @@
+package goutubedl_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wader/goutubedl"
+	"github.com/wader/goutubedl/livechat"
+)
+
+func dirFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	if err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, rel)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestDownloadToDirFake(t *testing.T) {
+	useFake(t)
+
+	result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("files", func(t *testing.T) {
+		dir := t.TempDir()
+		files, err := result.DownloadToDir(context.Background(), dir, goutubedl.DownloadToDirOptions{
+			OutputTemplate: "sub/%(id)s.%(ext)s",
+			WriteSubtitles: true,
+			WriteThumbnail: true,
+			WriteInfoJSON:  true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []goutubedl.DownloadedFile{
+			{Path: filepath.Join(dir, "sub/single.en.vtt"), Role: goutubedl.FileRoleSubtitle},
+			{Path: filepath.Join(dir, "sub/single.info.json"), Role: goutubedl.FileRoleInfoJSON},
+			{Path: filepath.Join(dir, "sub/single.jpg"), Role: goutubedl.FileRoleThumbnail},
+			{Path: filepath.Join(dir, "sub/single.mp4"), Role: goutubedl.FileRoleVideo},
+		}
+		if !reflect.DeepEqual(expected, files) {
+			t.Errorf("expected %v got %v", expected, files)
+		}
+		// no temp dir left
+		expectedDirFiles := []string{"sub", "sub/single.en.vtt", "sub/single.info.json", "sub/single.jpg", "sub/single.mp4"}
+		if actual := dirFiles(t, dir); !reflect.DeepEqual(expectedDirFiles, actual) {
+			t.Errorf("expected %v got %v", expectedDirFiles, actual)
+		}
+	})
+
+	t.Run("live_chat", func(t *testing.T) {
+		result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{
+			SubtitleLanguages: []string{"live_chat"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir := t.TempDir()
+		files, err := result.DownloadToDir(context.Background(), dir, goutubedl.DownloadToDirOptions{
+			OutputTemplate: "%(id)s.%(ext)s",
+			WriteSubtitles: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		chatPath := filepath.Join(dir, "single.live_chat.json")
+		expected := []goutubedl.DownloadedFile{
+			{Path: chatPath, Role: goutubedl.FileRoleSubtitle},
+			{Path: filepath.Join(dir, "single.mp4"), Role: goutubedl.FileRoleVideo},
+		}
+		if !reflect.DeepEqual(expected, files) {
+			t.Errorf("expected %v got %v", expected, files)
+		}
+
+		f, err := os.Open(chatPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		m, err := livechat.NewReader(f).Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.AuthorName != "Alice" || m.Text != "hello" {
+			t.Errorf("unexpected message %+v", m)
+		}
+	})
+
+	t.Run("default_template_and_exists", func(t *testing.T) {
+		dir := t.TempDir()
+		files, err := result.DownloadToDir(context.Background(), dir, goutubedl.DownloadToDirOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []goutubedl.DownloadedFile{
+			{Path: filepath.Join(dir, "Entry_single [single].mp4"), Role: goutubedl.FileRoleVideo},
+		}
+		if !reflect.DeepEqual(expected, files) {
+			t.Errorf("expected %v got %v", expected, files)
+		}
+
+		_, err = result.DownloadToDir(context.Background(), dir, goutubedl.DownloadToDirOptions{})
+		if !errors.Is(err, os.ErrExist) {
+			t.Errorf("expected exist error got %v", err)
+		}
+		if actual := dirFiles(t, dir); len(actual) != 1 {
+			t.Errorf("expected only first download got %v", actual)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		os.Setenv(fakeFailEnv, "single")
+		defer os.Unsetenv(fakeFailEnv)
+		dir := t.TempDir()
+		_, err := result.DownloadToDir(context.Background(), dir, goutubedl.DownloadToDirOptions{})
+		var ytErr goutubedl.YoutubedlError
+		if !errors.As(err, &ytErr) {
+			t.Errorf("expected youtube-dl error got %v", err)
+		}
+		if actual := dirFiles(t, dir); len(actual) != 0 {
+			t.Errorf("expected no files got %v", actual)
+		}
+	})
+
+	t.Run("invalid_template", func(t *testing.T) {
+		for _, template := range []string{"../%(id)s.%(ext)s", "/tmp/%(id)s.%(ext)s"} {
+			if _, err := result.DownloadToDir(context.Background(), t.TempDir(), goutubedl.DownloadToDirOptions{
+				OutputTemplate: template,
+			}); err == nil {
+				t.Errorf("%s: expected error", template)
+			}
+		}
+	})
+}
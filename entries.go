@@ -0,0 +1,190 @@
+package goutubedl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrEntryNotFound error when playlist entry is not found
+var ErrEntryNotFound = errors.New("playlist entry not found")
+
+// walkRawEntries calls fn with ID and raw JSON for each playlist entry until
+// fn returns false. Nested playlists are walked at most 2 levels deep, same as
+// Info.Entries. Entries are decoded one at a time to not have to keep a copy
+// of all entries in memory.
+func walkRawEntries(rawJSON []byte, fn func(id string, raw json.RawMessage) bool) error {
+	_, err := walkRawEntriesDepth(rawJSON, 0, fn)
+	return err
+}
+
+func walkRawEntriesDepth(rawJSON []byte, depth int, fn func(id string, raw json.RawMessage) bool) (bool, error) {
+	d := json.NewDecoder(bytes.NewReader(rawJSON))
+	if t, err := d.Token(); err != nil {
+		return false, err
+	} else if t != json.Delim('{') {
+		return false, fmt.Errorf("expected info object")
+	}
+
+	for d.More() {
+		t, err := d.Token()
+		if err != nil {
+			return false, err
+		}
+		if t != "entries" {
+			var skip json.RawMessage
+			if err := d.Decode(&skip); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		if t, err := d.Token(); err != nil {
+			return false, err
+		} else if t != json.Delim('[') {
+			// null entries
+			continue
+		}
+		for d.More() {
+			var raw json.RawMessage
+			if err := d.Decode(&raw); err != nil {
+				return false, err
+			}
+			var e struct {
+				ID   string `json:"id"`
+				Type string `json:"_type"`
+			}
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return false, err
+			}
+
+			if e.Type == "playlist" && depth == 0 {
+				if cont, err := walkRawEntriesDepth(raw, depth+1, fn); err != nil || !cont {
+					return cont, err
+				}
+				continue
+			}
+			if e.ID == "" {
+				// as we ignore errors for playlists some entries might show up as null
+				continue
+			}
+			if !fn(e.ID, raw) {
+				return false, nil
+			}
+		}
+		if _, err := d.Token(); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// rawEntries returns raw JSON for all playlist entries by ID
+func rawEntries(rawJSON []byte) (map[string]json.RawMessage, error) {
+	entries := map[string]json.RawMessage{}
+	err := walkRawEntries(rawJSON, func(id string, raw json.RawMessage) bool {
+		if _, ok := entries[id]; !ok {
+			entries[id] = raw
+		}
+		return true
+	})
+	return entries, err
+}
+
+// rawEntry returns raw JSON for playlist entry with ID
+func rawEntry(rawJSON []byte, entryID string) (json.RawMessage, error) {
+	var entry json.RawMessage
+	err := walkRawEntries(rawJSON, func(id string, raw json.RawMessage) bool {
+		if id == entryID {
+			entry = raw
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	} else if entry == nil {
+		return nil, fmt.Errorf("%s: %w", entryID, ErrEntryNotFound)
+	}
+	return entry, nil
+}
+
+func (result Result) rawEntry(entryID string) (json.RawMessage, error) {
+	if raw, ok := result.hydratedJSON[entryID]; ok {
+		return raw, nil
+	}
+	return rawEntry(result.RawJSON, entryID)
+}
+
+func (result Result) rawEntries() (map[string]json.RawMessage, error) {
+	entries, err := rawEntries(result.RawJSON)
+	if err != nil {
+		return nil, err
+	}
+	for id, raw := range result.hydratedJSON {
+		entries[id] = raw
+	}
+	return entries, nil
+}
+
+// Entry returns playlist entry with ID. Entries of nested playlists, ex:
+// channel tabs, are also searched, same depth as when downloading.
+func (result Result) Entry(entryID string) (Info, error) {
+	for _, e := range result.Info.Entries {
+		if e.Type == "playlist" {
+			for _, ee := range e.Entries {
+				if ee.ID == entryID {
+					return ee, nil
+				}
+			}
+			continue
+		}
+		if e.ID == entryID {
+			return e, nil
+		}
+	}
+	return Info{}, fmt.Errorf("%s: %w", entryID, ErrEntryNotFound)
+}
+
+// DownloadEntry downloads playlist entry with ID. The already fetched entry
+// info is used directly so playlist changes since New don't matter.
+func (result Result) DownloadEntry(
+	ctx context.Context,
+	entryID string,
+	options DownloadOptions,
+) (*DownloadResult, error) {
+	if result.Options.noInfoDownload {
+		return nil, errors.New("can't download playlist entry without info")
+	}
+
+	entry, err := result.Entry(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if options.PlaylistIndex != 0 || len(options.PlaylistItems) > 0 {
+		return nil, errors.New("playlist index and items options can't be used with entry download")
+	}
+	raw, err := result.rawEntry(entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.download(ctx, entry, raw, 0, options)
+}
+
+func (result Result) downloadEntry(
+	ctx context.Context,
+	entry Info,
+	entries map[string]json.RawMessage,
+	options DownloadOptions,
+) (*DownloadResult, error) {
+	raw, ok := entries[entry.ID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", entry.ID, ErrEntryNotFound)
+	}
+
+	return result.download(ctx, entry, raw, 0, options)
+}
@@ -0,0 +1,171 @@
+package goutubedl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Extractor youtube-dl extractor
+type Extractor struct {
+	Name        string
+	Description string // Empty for not working or hidden extractors
+	Working     bool   // False if marked as currently broken
+	// Extractor has age restricted content and is excluded with --age-limit 0
+	AgeRestricted bool
+}
+
+const genericExtractorName = "generic"
+const brokenExtractorSuffix = " (CURRENTLY BROKEN)"
+
+type extractorsCacheEntry struct {
+	modTime    time.Time
+	size       int64
+	extractors []Extractor
+}
+
+var extractorsCache = struct {
+	sync.Mutex
+	m map[string]extractorsCacheEntry
+}{m: map[string]extractorsCacheEntry{}}
+
+func outputLines(ctx context.Context, stdin string, args ...string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, ProbePath(), args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		if l := s.Text(); strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	return lines, s.Err()
+}
+
+// extractor names from --list-extractors output, indented lines are matched URLs
+func extractorNames(lines []string) []string {
+	var names []string
+	for _, l := range lines {
+		if strings.HasPrefix(l, " ") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(l, brokenExtractorSuffix))
+	}
+	return names
+}
+
+// ListExtractors returns all extractors using --list-extractors and
+// --extractor-descriptions. Result is cached per binary path and is only
+// fetched again if the binary file changes, ex: is updated to a new version.
+func ListExtractors(ctx context.Context) ([]Extractor, error) {
+	path, err := exec.LookPath(ProbePath())
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	extractorsCache.Lock()
+	defer extractorsCache.Unlock()
+	if e, ok := extractorsCache.m[path]; ok && e.modTime.Equal(fi.ModTime()) && e.size == fi.Size() {
+		return e.extractors, nil
+	}
+
+	listLines, err := outputLines(ctx, "", "--list-extractors")
+	if err != nil {
+		return nil, err
+	}
+	ageLimitLines, err := outputLines(ctx, "", "--list-extractors", "--age-limit", "0")
+	if err != nil {
+		return nil, err
+	}
+	descLines, err := outputLines(ctx, "", "--extractor-descriptions")
+	if err != nil {
+		return nil, err
+	}
+
+	ageLimitNames := map[string]bool{}
+	for _, n := range extractorNames(ageLimitLines) {
+		ageLimitNames[n] = true
+	}
+
+	var es []Extractor
+	index := map[string]int{}
+	for _, l := range listLines {
+		if strings.HasPrefix(l, " ") {
+			continue
+		}
+		name := strings.TrimSuffix(l, brokenExtractorSuffix)
+		index[name] = len(es)
+		es = append(es, Extractor{
+			Name:          name,
+			Working:       !strings.HasSuffix(l, brokenExtractorSuffix),
+			AgeRestricted: !ageLimitNames[name],
+		})
+	}
+
+	// description lines are "name" or "name: description" and names can
+	// include ":" so use longest known name prefix
+	for _, l := range descLines {
+		i, ok := index[l]
+		name := l
+		for j := strings.LastIndex(l, ":"); !ok && j != -1; j = strings.LastIndex(l[0:j], ":") {
+			name = l[0:j]
+			i, ok = index[name]
+		}
+		if !ok {
+			continue
+		}
+		es[i].Description = strings.TrimSpace(strings.TrimPrefix(l[len(name):], ":"))
+	}
+
+	extractorsCache.m[path] = extractorsCacheEntry{
+		modTime:    fi.ModTime(),
+		size:       fi.Size(),
+		extractors: es,
+	}
+
+	return es, nil
+}
+
+// SupportsURL returns name of extractor that would handle URL and if it's
+// supported. URLs only handled by the generic extractor are not considered
+// supported. Uses --list-extractors which only matches URL patterns so no
+// network access is done.
+func SupportsURL(ctx context.Context, rawURL string) (string, bool, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	// provide url via stdin for security, youtube-dl has some run command args
+	lines, err := outputLines(ctx, rawURL+"\n", "--list-extractors", "--batch-file", "-")
+	if err != nil {
+		return "", false, err
+	}
+
+	name := ""
+	for _, l := range lines {
+		if !strings.HasPrefix(l, " ") {
+			name = strings.TrimSuffix(l, brokenExtractorSuffix)
+			continue
+		}
+		// first matching extractor is the one used
+		if strings.TrimSpace(l) == rawURL {
+			return name, name != genericExtractorName, nil
+		}
+	}
+
+	return "", false, nil
+}
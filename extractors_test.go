@@ -0,0 +1,40 @@
+package goutubedl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestExtractorsFake(t *testing.T) {
+	useFake(t)
+
+	es, err := goutubedl.ListExtractors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(es) != 2 || es[0].Name != "Fake" || es[0].Description != "fake extractor" {
+		t.Errorf("unexpected extractors %v", es)
+	}
+
+	for _, c := range []struct {
+		url               string
+		expectedName      string
+		expectedSupported bool
+	}{
+		{fakeSingleURL, "Fake", true},
+		{" " + fakeSingleURL + "\n", "Fake", true},
+		{"https://example.com/video", "generic", false},
+	} {
+		t.Run(c.url, func(t *testing.T) {
+			name, supported, err := goutubedl.SupportsURL(context.Background(), c.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != c.expectedName || supported != c.expectedSupported {
+				t.Errorf("expected %s %v got %s %v", c.expectedName, c.expectedSupported, name, supported)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+package goutubedl_test
+
+// Fake youtube-dl used by tests that should not depend on network access.
+// The test binary re-executes itself as youtube-dl when fakeEnv is set.
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl"
+)
+
+const (
+	fakeEnv      = "GOUTUBEDL_FAKE"
+	fakeCountEnv = "GOUTUBEDL_FAKE_COUNT" // number of entries in fake://playlist (default 5)
+	fakeFailEnv  = "GOUTUBEDL_FAKE_FAIL"  // entry id that fails after download has started
+	fakeHTTPEnv  = "GOUTUBEDL_FAKE_HTTP"  // base URL for side assets like subtitles
+	fakeSlowEnv  = "GOUTUBEDL_FAKE_SLOW"  // block when downloading to a file, like a long download and merge
+
+	fakeSingleURL   = "fake://single"
+	fakePlaylistURL = "fake://playlist"
+	fakeChannelURL  = "fake://channel"
+	fakeEntryURL    = "fake://entry/" // followed by entry id
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeEnv) != "" {
+		os.Exit(fakeYoutubedl(os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+// useFake makes goutubedl use the fake youtube-dl until test cleanup
+func useFake(t *testing.T) {
+	origPath := goutubedl.Path
+	goutubedl.Path = os.Args[0]
+	os.Setenv(fakeEnv, "1")
+	t.Cleanup(func() {
+		goutubedl.Path = origPath
+		os.Unsetenv(fakeEnv)
+		os.Unsetenv(fakeCountEnv)
+		os.Unsetenv(fakeFailEnv)
+		os.Unsetenv(fakeHTTPEnv)
+	})
+}
+
+// fakeLiveChat live chat replay with one message as written by youtube-dl
+const fakeLiveChat = `{"replayChatItemAction": {"actions": [{"addChatItemAction": {"item": {"liveChatTextMessageRenderer": {"message": {"runs": [{"text": "hello"}]}, "authorName": {"simpleText": "Alice"}, "id": "m1", "timestampUsec": "1600000000000000"}}}}], "videoOffsetTimeMsec": "1500"}}
+`
+
+func fakeEntry(id string, index int, flat bool) map[string]interface{} {
+	if flat {
+		return map[string]interface{}{
+			"_type":          "url",
+			"id":             id,
+			"title":          "Entry " + id,
+			"url":            fakeEntryURL + id,
+			"ie_key":         "Fake",
+			"playlist_index": index,
+		}
+	}
+	info := map[string]interface{}{
+		"id":             id,
+		"title":          "Entry " + id,
+		"extractor":      "fake",
+		"extractor_key":  "Fake",
+		"playlist_index": index,
+		"duration":       60,
+		"formats": []map[string]interface{}{
+			{"format_id": "f1", "ext": "mp4", "protocol": "https"},
+		},
+		"heatmap": []map[string]interface{}{
+			{"start_time": 0, "end_time": 30, "value": 0.5},
+			{"start_time": 30, "end_time": 60, "value": 1},
+		},
+	}
+
+	if base := os.Getenv(fakeHTTPEnv); base != "" {
+		info["thumbnail"] = base + "/thumb.jpg"
+		info["http_headers"] = map[string]string{"X-Fake": "1"}
+		storyboard := func(id string, width int, height int) map[string]interface{} {
+			return map[string]interface{}{
+				"format_id": id, "format_note": "storyboard", "ext": "mhtml", "protocol": "mhtml",
+				"width": width, "height": height, "rows": 2, "columns": 2,
+				"http_headers": map[string]string{"X-Storyboard": id},
+				"fragments": []map[string]interface{}{
+					{"url": base + "/" + id + "/0.webp", "duration": 40},
+					{"url": base + "/" + id + "/1.webp", "duration": 20},
+				},
+			}
+		}
+		info["formats"] = []map[string]interface{}{
+			storyboard("sb1", 24, 14),
+			storyboard("sb0", 48, 27),
+			{"format_id": "f1", "ext": "mp4", "protocol": "https"},
+		}
+		info["thumbnails"] = []map[string]interface{}{
+			{"id": "small", "url": base + "/small.jpg", "preference": -10, "width": 120, "height": 90},
+			{"id": "medium", "url": base + "/medium.png", "preference": -5, "width": 480, "height": 360},
+			{"id": "large", "url": base + "/large.webp", "preference": 0, "width": 1280, "height": 720},
+			{"id": "unsized", "url": base + "/unsized.jpg", "preference": -20},
+		}
+		subs := func(auto bool, langExts map[string][]string) map[string]interface{} {
+			m := map[string]interface{}{}
+			for lang, exts := range langExts {
+				var ss []map[string]interface{}
+				for _, ext := range exts {
+					u := base + "/sub/" + lang + "." + ext
+					if auto {
+						u += "?auto"
+					}
+					s := map[string]interface{}{"ext": ext, "url": u}
+					if lang == "live_chat" {
+						s["url"] = base + "/watch"
+						s["protocol"] = "youtube_live_chat_replay"
+					}
+					ss = append(ss, s)
+				}
+				m[lang] = ss
+			}
+			return m
+		}
+		info["subtitles"] = subs(false, map[string][]string{
+			"en":        {"vtt", "srt"},
+			"sv":        {"vtt"},
+			"live_chat": {"json"},
+		})
+		info["automatic_captions"] = subs(true, map[string][]string{
+			"en": {"vtt"},
+			"de": {"vtt", "srt"},
+		})
+	}
+
+	return info
+}
+
+// fakePlaylist returns count entries newest first, "e<count>" to "e1",
+// sliced by playlist start and end
+func fakePlaylist(id string, prefix string, count int, start int, end int, flat bool) map[string]interface{} {
+	var entries []interface{}
+	for i := 1; i <= count; i++ {
+		if i < start || (end > 0 && i > end) {
+			continue
+		}
+		entries = append(entries, fakeEntry(prefix+strconv.Itoa(count-i+1), i, flat))
+	}
+	return map[string]interface{}{
+		"_type":          "playlist",
+		"id":             id,
+		"title":          "Playlist " + id,
+		"extractor":      "fake",
+		"extractor_key":  "Fake",
+		"playlist_count": count,
+		"entries":        entries,
+	}
+}
+
+func fakeChannel() map[string]interface{} {
+	return map[string]interface{}{
+		"_type":                  "playlist",
+		"id":                     "channel",
+		"channel":                "Channel",
+		"channel_id":             "channel",
+		"channel_url":            fakeChannelURL,
+		"channel_follower_count": 123,
+		"title":                  "Channel",
+		"description":            "Channel description",
+		"thumbnails": []map[string]interface{}{
+			{"id": "banner_uncropped", "url": "fake://banner.jpg"},
+			{"id": "avatar_uncropped", "url": "fake://avatar.jpg"},
+		},
+	}
+}
+
+// fakeChannelTab returns "videos" tab with entries "v3" to "v1" and "shorts"
+// with "s2" to "s1"
+func fakeChannelTab(tab string, start int, end int, flat bool) map[string]interface{} {
+	count := 3
+	if tab == "shorts" {
+		count = 2
+	}
+	p := fakePlaylist("channel", tab[0:1], count, start, end, flat)
+	p["title"] = "Channel - " + tab
+	p["webpage_url"] = fakeChannelURL + "/" + tab
+	return p
+}
+
+func fakeYoutubedl(args []string) int {
+	has := func(a string) bool {
+		for _, arg := range args {
+			if arg == a {
+				return true
+			}
+		}
+		return false
+	}
+	value := func(a string) string {
+		for i, arg := range args {
+			if arg == a && i+1 < len(args) {
+				return args[i+1]
+			}
+		}
+		return ""
+	}
+	intValue := func(a string) int {
+		n, _ := strconv.Atoi(value(a))
+		return n
+	}
+
+	switch {
+	case has("--version"):
+		fmt.Println("2026.01.01")
+	case has("--list-extractors"):
+		fmt.Println("Fake")
+		var generic []string
+		if has("--batch-file") {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				if strings.HasPrefix(scanner.Text(), "fake://") {
+					fmt.Println(" " + scanner.Text())
+				} else {
+					generic = append(generic, scanner.Text())
+				}
+			}
+		}
+		fmt.Println("generic")
+		for _, u := range generic {
+			fmt.Println(" " + u)
+		}
+	case has("--extractor-descriptions"):
+		fmt.Println("Fake: fake extractor")
+		fmt.Println("generic")
+	case has("--dump-single-json"):
+		rawURL, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		rawURL = strings.TrimSpace(rawURL)
+		flat := has("--flat-playlist")
+		start, end := intValue("--playlist-start"), intValue("--playlist-end")
+
+		var info map[string]interface{}
+		switch {
+		case strings.HasPrefix(rawURL, fakeEntryURL):
+			info = fakeEntry(strings.TrimPrefix(rawURL, fakeEntryURL), 0, false)
+		case rawURL == fakeSingleURL:
+			info = fakeEntry("single", 0, false)
+		case rawURL == fakePlaylistURL:
+			count := 5
+			if n, err := strconv.Atoi(os.Getenv(fakeCountEnv)); err == nil {
+				count = n
+			}
+			info = fakePlaylist("playlist", "e", count, start, end, flat)
+		case rawURL == fakeChannelURL:
+			// tabs as nested playlists, each numbering its entries from 1
+			info = fakeChannel()
+			info["entries"] = []interface{}{
+				fakeChannelTab("videos", start, end, flat),
+				fakeChannelTab("shorts", start, end, flat),
+			}
+		case rawURL == fakeChannelURL+"/videos" || rawURL == fakeChannelURL+"/shorts":
+			info = fakeChannelTab(strings.TrimPrefix(rawURL, fakeChannelURL+"/"), start, end, flat)
+			for k, v := range fakeChannel() {
+				if _, ok := info[k]; !ok {
+					info[k] = v
+				}
+			}
+		case strings.HasPrefix(rawURL, fakeChannelURL+"/"):
+			fmt.Fprintln(os.Stderr, "ERROR: This channel does not have a tab")
+			return 1
+		default:
+			fmt.Fprintln(os.Stderr, "ERROR: Unsupported URL: "+rawURL)
+			return 1
+		}
+		if has("--write-comments") {
+			info["comments"] = []map[string]interface{}{
+				{"id": "c1", "parent": "root", "text": "first", "author": "a", "like_count": 10, "is_pinned": true},
+				{"id": "c1.r1", "parent": "c1", "text": "reply", "author": "b", "is_favorited": true},
+				{"id": "c2", "parent": "root", "text": "second", "author": "c", "timestamp": 1700000000},
+				{"id": "c1.r2", "parent": "c1", "text": "reply 2", "author": "a", "author_is_uploader": true},
+			}
+		}
+		// lets tests check arguments using RawJSON
+		info["fake_args"] = args
+		_ = json.NewEncoder(os.Stdout).Encode(info)
+	case has("--load-info"):
+		b, err := os.ReadFile(value("--load-info"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "ERROR: "+err.Error())
+			return 1
+		}
+		var info struct {
+			ID      string            `json:"id"`
+			Title   string            `json:"title"`
+			Entries []json.RawMessage `json:"entries"`
+		}
+		if err := json.Unmarshal(b, &info); err != nil {
+			fmt.Fprintln(os.Stderr, "ERROR: "+err.Error())
+			return 1
+		}
+		if info.Entries != nil {
+			i := intValue("--playlist-items")
+			if i < 1 || i > len(info.Entries) {
+				fmt.Fprintln(os.Stderr, "ERROR: invalid playlist items")
+				return 1
+			}
+			_ = json.Unmarshal(info.Entries[i-1], &info)
+		}
+
+		// downloaded data is the loaded info JSON, for a playlist the whole
+		// playlist so that tests can tell if only the entry was loaded
+		if output := value("--output"); output != "-" {
+			// only id, title and ext fields
+			output = strings.NewReplacer("%(id)s", info.ID, "%(title)s", strings.ReplaceAll(info.Title, " ", "_")).Replace(output)
+			write := func(ext string, data []byte) {
+				p := strings.ReplaceAll(output, "%(ext)s", ext)
+				_ = os.MkdirAll(filepath.Dir(p), 0755)
+				_ = os.WriteFile(p, data, 0600)
+			}
+			ext := "mp4"
+			if v := value("--merge-output-format"); v != "" {
+				ext = v
+			}
+			// same as youtube-dl progress is on stdout when not downloading to stdout
+			fmt.Println("[download] Destination: " + strings.ReplaceAll(output, "%(ext)s", ext))
+			if os.Getenv(fakeSlowEnv) != "" {
+				time.Sleep(time.Minute)
+			}
+			write(ext, b)
+			if has("--write-subs") {
+				if strings.Contains(value("--sub-langs"), "live_chat") {
+					write("live_chat.json", []byte(fakeLiveChat))
+				} else {
+					write("en.vtt", []byte("WEBVTT\n"))
+				}
+			}
+			if has("--write-thumbnail") {
+				write("jpg", nil)
+			}
+			if has("--write-info-json") {
+				write("info.json", b)
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, "[download] Destination: -")
+			os.Stdout.Write(b)
+		}
+		if info.ID == os.Getenv(fakeFailEnv) {
+			fmt.Fprintln(os.Stderr, "ERROR: fake failure")
+			return 1
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "ERROR: unsupported fake arguments")
+		return 1
+	}
+
+	return 0
+}
+
+// fakeArgs returns youtube-dl arguments used to get result info
+func fakeArgs(t *testing.T, result goutubedl.Result) []string {
+	t.Helper()
+	var v struct {
+		FakeArgs []string `json:"fake_args"`
+	}
+	if err := json.Unmarshal(result.RawJSON, &v); err != nil {
+		t.Fatal(err)
+	}
+	return v.FakeArgs
+}
+
+// readFakeDownload reads and closes dr and returns the entry info written by
+// the fake youtube-dl as download data
+func readFakeDownload(t *testing.T, dr *goutubedl.DownloadResult) (goutubedl.Info, error) {
+	t.Helper()
+	b, readErr := io.ReadAll(dr)
+	closeErr := dr.Close()
+	if readErr != nil {
+		return goutubedl.Info{}, readErr
+	}
+	var info goutubedl.Info
+	if err := json.Unmarshal(b, &info); err != nil {
+		t.Fatalf("failed to parse fake download %q: %s", b, err)
+	}
+	return info, closeErr
+}
@@ -0,0 +1,308 @@
+package goutubedl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FetchOptions options for fetching thumbnails and subtitles
+type FetchOptions struct {
+	// Max number of concurrent fetches (default 4)
+	Concurrency int
+	// Number of retries on network errors and 429 and 5xx responses
+	Retries int
+	// Delay before first retry, doubled for each following retry (default 1s)
+	RetryDelay time.Duration
+	// Max size in bytes of an asset, zero is no limit
+	MaxSize int64
+}
+
+// ErrAssetTooLarge asset is larger than FetchOptions.MaxSize
+var ErrAssetTooLarge = errors.New("asset too large")
+
+// AssetError error fetching a thumbnail or subtitle
+type AssetError struct {
+	URL string
+	Err error
+}
+
+func (e AssetError) Error() string {
+	return fmt.Sprintf("%s: %s", e.URL, e.Err)
+}
+
+func (e AssetError) Unwrap() error {
+	return e.Err
+}
+
+// statusError non-2xx HTTP response
+type statusError struct {
+	statusCode int
+	status     string
+}
+
+func (e statusError) Error() string {
+	return "http status " + e.status
+}
+
+func (e statusError) retryable() bool {
+	return e.statusCode == http.StatusTooManyRequests || e.statusCode >= 500
+}
+
+type fetchJob struct {
+	url string
+	fn  func(b []byte, contentType string)
+}
+
+type fetcher struct {
+	client  *http.Client
+	options FetchOptions
+	header  http.Header
+	cookies []fileCookie
+}
+
+// newFetcher returns a fetcher using same proxy, cookies file, referer and
+// headers as youtube-dl would. Proxy is only used if options.HTTPClient is nil.
+func newFetcher(options Options, headers map[string]string) (*fetcher, error) {
+	client := options.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+		if options.ProxyUrl != "" {
+			proxyURL, err := url.Parse(options.ProxyUrl)
+			if err != nil {
+				return nil, fmt.Errorf("proxy: %w", err)
+			}
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.Proxy = http.ProxyURL(proxyURL)
+			client = &http.Client{Transport: transport}
+		}
+	}
+
+	f := &fetcher{
+		client:  client,
+		options: options.Fetch,
+		header:  http.Header{},
+	}
+	if f.options.Concurrency < 1 {
+		f.options.Concurrency = 4
+	}
+	if f.options.RetryDelay == 0 {
+		f.options.RetryDelay = time.Second
+	}
+	for k, v := range headers {
+		f.header.Set(k, v)
+	}
+	if options.Referer != "" {
+		f.header.Set("Referer", options.Referer)
+	}
+	if options.Cookies != "" {
+		cookies, err := readCookieFile(options.Cookies)
+		if err != nil {
+			return nil, err
+		}
+		f.cookies = cookies
+	}
+
+	return f, nil
+}
+
+func (f *fetcher) get(ctx context.Context, rawURL string) ([]byte, string, error) {
+	r, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	r = r.WithContext(ctx)
+	for k, vs := range f.header {
+		r.Header[k] = vs
+	}
+	for _, c := range f.cookies {
+		if c.matches(r.URL) {
+			r.AddCookie(&http.Cookie{Name: c.name, Value: c.value})
+		}
+	}
+
+	resp, err := f.client.Do(r)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", statusError{statusCode: resp.StatusCode, status: resp.Status}
+	}
+	if f.options.MaxSize > 0 && resp.ContentLength > f.options.MaxSize {
+		return nil, "", ErrAssetTooLarge
+	}
+
+	var body io.Reader = resp.Body
+	if f.options.MaxSize > 0 {
+		body = io.LimitReader(resp.Body, f.options.MaxSize+1)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", err
+	}
+	if f.options.MaxSize > 0 && int64(len(b)) > f.options.MaxSize {
+		return nil, "", ErrAssetTooLarge
+	}
+
+	return b, resp.Header.Get("Content-Type"), nil
+}
+
+func (f *fetcher) getWithRetries(ctx context.Context, rawURL string) ([]byte, string, error) {
+	delay := f.options.RetryDelay
+	for attempt := 0; ; attempt++ {
+		b, contentType, err := f.get(ctx, rawURL)
+		if err == nil {
+			return b, contentType, nil
+		}
+
+		var se statusError
+		retryable := !errors.Is(err, ErrAssetTooLarge) &&
+			(!errors.As(err, &se) || se.retryable())
+		if !retryable || attempt >= f.options.Retries || ctx.Err() != nil {
+			return nil, "", err
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// fetch runs jobs using bounded concurrency, returns errors in job order
+func (f *fetcher) fetch(ctx context.Context, jobs []fetchJob) []AssetError {
+	errs := make([]error, len(jobs))
+	sem := make(chan struct{}, f.options.Concurrency)
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, j fetchJob) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			b, contentType, err := f.getWithRetries(ctx, j.url)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			j.fn(b, contentType)
+		}(i, j)
+	}
+	wg.Wait()
+
+	var assetErrs []AssetError
+	for i, err := range errs {
+		if err != nil {
+			assetErrs = append(assetErrs, AssetError{URL: jobs[i].url, Err: err})
+		}
+	}
+	return assetErrs
+}
+
+// fetchAssets fetches thumbnails and subtitles selected by options
+func fetchAssets(ctx context.Context, info *Info, options Options) ([]AssetError, error) {
+	var jobs []fetchJob
+
+	if options.DownloadThumbnail {
+		jobs = append(jobs, thumbnailJobs(info, options.Thumbnails)...)
+	}
+
+	if options.DownloadSubtitles {
+		subtitles, err := options.selectedSubtitles(info)
+		if err != nil {
+			return nil, err
+		}
+		for _, subtitle := range subtitles {
+			// not fetchable with a GET, ex: live chat replay URL is the watch page
+			if !subtitle.isHTTP() {
+				continue
+			}
+			subtitle := subtitle
+			jobs = append(jobs, fetchJob{
+				url: subtitle.URL,
+				fn:  func(b []byte, _ string) { subtitle.Bytes = b },
+			})
+		}
+	}
+
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	f, err := newFetcher(options, info.HTTPHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.fetch(ctx, jobs), nil
+}
+
+// fileCookie cookie from a Netscape cookies file as used by --cookies
+type fileCookie struct {
+	domain            string
+	includeSubdomains bool
+	path              string
+	secure            bool
+	name              string
+	value             string
+}
+
+func (c fileCookie) matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	domain := strings.TrimPrefix(c.domain, ".")
+	if host != domain && !(c.includeSubdomains && strings.HasSuffix(host, "."+domain)) {
+		return false
+	}
+	if c.secure && u.Scheme != "https" {
+		return false
+	}
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	return strings.HasPrefix(p, c.path)
+}
+
+func readCookieFile(path string) ([]fileCookie, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cookies []fileCookie
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			continue
+		}
+		cookies = append(cookies, fileCookie{
+			domain:            strings.ToLower(fields[0]),
+			includeSubdomains: fields[1] == "TRUE",
+			path:              fields[2],
+			secure:            fields[3] == "TRUE",
+			name:              fields[5],
+			value:             fields[6],
+		})
+	}
+
+	return cookies, scanner.Err()
+}
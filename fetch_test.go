@@ -0,0 +1,123 @@
+package goutubedl_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestFetchFake(t *testing.T) {
+	useFake(t)
+
+	var mu sync.Mutex
+	requests := map[string]int{}
+	var headers http.Header
+	ts := fakeHTTPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		if r.URL.Path == "/thumb.jpg" {
+			headers = r.Header.Clone()
+		}
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/sub/sv.vtt":
+			http.NotFound(w, r)
+			return
+		case "/sub/en.srt":
+			// fail first request
+			if n == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/sub/en.vtt":
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+
+	cookiesPath := filepath.Join(t.TempDir(), "cookies.txt")
+	host := strings.Split(strings.TrimPrefix(ts.URL, "http://"), ":")[0]
+	if err := os.WriteFile(cookiesPath, []byte(
+		"# Netscape HTTP Cookie File\n"+
+			host+"\tFALSE\t/\tFALSE\t0\ta\t1\n"+
+			host+"\tFALSE\t/other\tFALSE\t0\tb\t2\n"+
+			"other.com\tTRUE\t/\tFALSE\t0\tc\t3\n",
+	), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{
+		DownloadThumbnail: true,
+		DownloadSubtitles: true,
+		Referer:           "http://referer",
+		Cookies:           cookiesPath,
+		Fetch: goutubedl.FetchOptions{
+			Concurrency: 2,
+			Retries:     1,
+			RetryDelay:  time.Millisecond,
+			MaxSize:     50,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(result.Info.ThumbnailBytes) != "/thumb.jpg" {
+		t.Errorf("unexpected thumbnail %q", result.Info.ThumbnailBytes)
+	}
+	for k, v := range map[string]string{"X-Fake": "1", "Referer": "http://referer", "Cookie": "a=1"} {
+		if headers.Get(k) != v {
+			t.Errorf("expected header %s %q got %q", k, v, headers.Get(k))
+		}
+	}
+
+	bytes := map[string]string{}
+	for lang, ss := range result.Info.Subtitles {
+		for _, s := range ss {
+			bytes[lang+"."+s.Ext] = string(s.Bytes)
+		}
+	}
+	expectedBytes := map[string]string{
+		"en.vtt":         "",
+		"en.srt":         "/sub/en.srt",
+		"sv.vtt":         "",
+		"live_chat.json": "",
+	}
+	if !reflect.DeepEqual(expectedBytes, bytes) {
+		t.Errorf("expected %v got %v", expectedBytes, bytes)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requests["/watch"] != 0 {
+		t.Errorf("expected live chat subtitle to not be fetched")
+	}
+	if requests["/sub/en.srt"] != 2 {
+		t.Errorf("expected 2 requests for retried subtitle got %d", requests["/sub/en.srt"])
+	}
+
+	errs := map[string]error{}
+	for _, e := range result.AssetErrors {
+		errs[strings.TrimPrefix(e.URL, ts.URL)] = e
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 asset errors got %v", result.AssetErrors)
+	}
+	if err := errs["/sub/sv.vtt"]; err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected 404 error got %v", err)
+	}
+	if err := errs["/sub/en.vtt"]; !errors.Is(err, goutubedl.ErrAssetTooLarge) {
+		t.Errorf("expected too large error got %v", err)
+	}
+}
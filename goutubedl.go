@@ -88,6 +88,7 @@ type Info struct {
 	EndTime            float64 `json:"end_time"`             // Time in seconds where the reproduction should end, as specified in the URL
 	Extractor          string  `json:"extractor"`            // Name of the extractor
 	ExtractorKey       string  `json:"extractor_key"`        // Key name of the extractor
+	IEKey              string  `json:"ie_key"`               // Key name of the extractor for flat playlist entries
 	Epoch              float64 `json:"epoch"`                // Unix epoch when creating the file
 	Autonumber         float64 `json:"autonumber"`           // Five-digit number that will be increased with each download, starting at zero
 	Playlist           string  `json:"playlist"`             // Name or id of the playlist that contains the video
@@ -96,6 +97,11 @@ type Info struct {
 	PlaylistTitle      string  `json:"playlist_title"`       // Playlist title
 	PlaylistUploader   string  `json:"playlist_uploader"`    // Full name of the playlist uploader
 	PlaylistUploaderID string  `json:"playlist_uploader_id"` // Nickname or id of the playlist uploader
+	PlaylistCount      float64 `json:"playlist_count"`       // Total number of entries in the playlist, if known
+
+	// Available for channels and entries uploaded on a channel:
+	ChannelURL           string  `json:"channel_url"`            // Full URL to a channel webpage
+	ChannelFollowerCount float64 `json:"channel_follower_count"` // Number of followers of the channel
 
 	// Available for the video that belongs to some logical chapter or section:
 	Chapter       string  `json:"chapter"`        // Name or title of the chapter the video belongs to
@@ -134,9 +140,21 @@ type Info struct {
 
 	Formats   []Format              `json:"formats"`
 	Subtitles map[string][]Subtitle `json:"subtitles"`
+	// Automatically generated captions, usually speech recognition or translations
+	AutomaticCaptions map[string][]Subtitle `json:"automatic_captions"`
+	// don't unmarshal, storyboard formats removed from Formats unless
+	// Options.IncludeStoryboards is set
+	Storyboards []Format `json:"-"`
+	// "Most replayed" segments
+	Heatmap []HeatmapSegment `json:"heatmap"`
+
+	// Comments if Options.Comments is set, see CommentTree
+	Comments []Comment `json:"comments"`
 
 	// Playlist entries if _type is playlist
 	Entries []Info `json:"entries"`
+	// don't unmarshal, channel tab the entry was found in for flattened channel entries
+	ChannelTab ChannelTab `json:"-"`
 
 	// Info can also be a mix of Info and one Format
 	Format
@@ -149,6 +167,11 @@ type Thumbnail struct {
 	Width      int    `json:"width"`
 	Height     int    `json:"height"`
 	Resolution string `json:"resolution"`
+
+	// don't unmarshal, populated if selected by Options.Thumbnails
+	Bytes []byte `json:"-"`
+	// don't unmarshal, detected content type of Bytes, ex: "image/jpeg"
+	ContentType string `json:"-"`
 }
 
 // Format youtube-dl downloadable format
@@ -172,13 +195,27 @@ type Format struct {
 	FilesizeApprox float64           `json:"filesize_approx"` // An estimate for the number of bytes
 	Protocol       string            `json:"protocol"`        // The protocol that will be used for the actual download
 	HTTPHeaders    map[string]string `json:"http_headers"`
+
+	// Available for storyboard formats, see IsStoryboard:
+	Rows      int        `json:"rows"`      // Number of tile rows in a sprite sheet
+	Columns   int        `json:"columns"`   // Number of tile columns in a sprite sheet
+	Fragments []Fragment `json:"fragments"` // Fragments, for storyboards the sprite sheets
+}
+
+// Fragment part of a format
+type Fragment struct {
+	URL      string  `json:"url"`
+	Duration float64 `json:"duration"` // Duration in seconds
 }
 
 // Subtitle youtube-dl subtitle
 type Subtitle struct {
 	URL      string `json:"url"`
 	Ext      string `json:"ext"`
+	Protocol string `json:"protocol"` // empty for plain HTTP, ex: "youtube_live_chat_replay"
 	Language string `json:"-"`
+	// don't unmarshal, true if from automatic captions
+	Automatic bool `json:"-"`
 	// don't unmarshal, populated from subtitle file
 	Bytes []byte `json:"-"`
 }
@@ -223,10 +260,24 @@ type Options struct {
 	FlatPlaylist      bool   // --flat-playlist, faster fetching but with less video info for playlists
 	Downloader        string // --downloader
 	DownloadThumbnail bool
+	// Thumbnails to download if DownloadThumbnail is set. Zero value downloads
+	// only Info.Thumbnail.
+	Thumbnails        ThumbnailSelector
 	DownloadSubtitles bool
-	DownloadSections  string // --download-sections
-	Referer           string // --referer
-	Impersonate       string // --impersonate
+	// Subtitle languages to download, same as --sub-langs, regexps and "all"
+	// optionally prefixed with "-" to exclude, ex: "all", "-live_chat".
+	// Default all languages. Subtitles not served over HTTP, ex: live chat
+	// replay, are not downloaded.
+	SubtitleLanguages []string
+	// Preferred subtitle formats in order, ex: "vtt", "srt", "ttml", "json3".
+	// Default all formats.
+	SubtitleFormats []string
+	// Also download automatic captions for languages without subtitles (--write-auto-subs)
+	AutomaticCaptions bool
+	// Deprecated: use DownloadOptions.Sections
+	DownloadSections string // --download-sections
+	Referer          string // --referer
+	Impersonate      string // --impersonate
 
 	ProxyUrl           string // --proxy URL  http://host:port or socks5://host:port
 	UseIPV4            bool   // -4 Make all connections via IPv4
@@ -234,13 +285,45 @@ type Options struct {
 	CookiesFromBrowser string // --cookies-from-browser BROWSER[:FOLDER]
 	DebugLog           Printer
 	StderrFn           func(cmd *exec.Cmd) io.Writer // if not nil, function to get Writer for stderr
-	HTTPClient         *http.Client                  // Client for download thumbnail and subtitles (nil use http.DefaultClient)
+	HTTPClient         *http.Client                  // Client for download thumbnail and subtitles (nil use http.DefaultClient with ProxyUrl)
 	MergeOutputFormat  string                        // --merge-output-format
 	SortingFormat      string                        // --format-sort
+	MatchFilter        MatchFilter                   // Filter playlist entries
+	// For TypeChannel, tabs to fetch. Each tab is fetched separately and
+	// entries are tagged with the tab. Default is tabs youtube-dl returns for the URL.
+	ChannelTabs []ChannelTab
+	// Keep storyboard formats in Info.Formats, they are always in Info.Storyboards
+	IncludeStoryboards bool
+	// Extract comments into Info.Comments (--write-comments)
+	Comments       bool
+	CommentOptions CommentOptions
+
+	// Concurrency, retries and size limit when fetching thumbnail and subtitles
+	Fetch FetchOptions
 
 	// Set to true if you don't want to use the result.Info structure after the goutubedl.New() call,
 	// so the given URL will be downloaded in a single pass in the DownloadResult.Download() call.
 	noInfoDownload bool
+	// Pass playlist start, end and flat options also for TypeAny, used by Detect
+	anyPlaylistArgs bool
+}
+
+func playlistArgs(options Options) []string {
+	var args []string
+	if options.PlaylistStart > 0 {
+		args = append(args,
+			"--playlist-start", strconv.Itoa(int(options.PlaylistStart)),
+		)
+	}
+	if options.PlaylistEnd > 0 {
+		args = append(args,
+			"--playlist-end", strconv.Itoa(int(options.PlaylistEnd)),
+		)
+	}
+	if options.FlatPlaylist {
+		args = append(args, "--flat-playlist")
+	}
+	return args
 }
 
 // Version of youtube-dl.
@@ -284,7 +367,18 @@ func New(ctx context.Context, rawURL string, options Options) (result Result, er
 		}, nil
 	}
 
-	info, rawJSON, err := infoFromURL(ctx, rawURL, options)
+	var info Info
+	var rawJSON []byte
+	if options.Type == TypeChannel && len(options.ChannelTabs) > 0 {
+		info, rawJSON, err = channelTabsInfo(ctx, rawURL, options)
+	} else {
+		info, rawJSON, err = infoFromURL(ctx, rawURL, options)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	assetErrs, err := fetchAssets(ctx, &info, options)
 	if err != nil {
 		return Result{}, err
 	}
@@ -293,10 +387,11 @@ func New(ctx context.Context, rawURL string, options Options) (result Result, er
 	copy(rawJSONCopy, rawJSON)
 
 	return Result{
-		Info:    info,
-		RawURL:  rawURL,
-		RawJSON: rawJSONCopy,
-		Options: options,
+		Info:        info,
+		RawURL:      rawURL,
+		RawJSON:     rawJSONCopy,
+		Options:     options,
+		AssetErrors: assetErrs,
 	}, nil
 }
 
@@ -354,35 +449,33 @@ func infoFromURL(
 	switch options.Type {
 	case TypePlaylist, TypeChannel:
 		cmd.Args = append(cmd.Args, "--yes-playlist")
-
-		if options.PlaylistStart > 0 {
-			cmd.Args = append(cmd.Args,
-				"--playlist-start", strconv.Itoa(int(options.PlaylistStart)),
-			)
-		}
-		if options.PlaylistEnd > 0 {
-			cmd.Args = append(cmd.Args,
-				"--playlist-end", strconv.Itoa(int(options.PlaylistEnd)),
-			)
-		}
-		if options.FlatPlaylist {
-			cmd.Args = append(cmd.Args, "--flat-playlist")
-		}
+		cmd.Args = append(cmd.Args, playlistArgs(options)...)
 	case TypeSingle:
-		if options.DownloadSubtitles {
-			cmd.Args = append(cmd.Args,
-				"--all-subs",
-			)
-		}
 		cmd.Args = append(cmd.Args,
 			"--no-playlist",
 		)
 	case TypeAny:
-		break
+		if options.anyPlaylistArgs {
+			cmd.Args = append(cmd.Args, playlistArgs(options)...)
+		}
 	default:
 		return Info{}, nil, fmt.Errorf("unhandled options type value: %d", options.Type)
 	}
 
+	cmd.Args = append(cmd.Args, options.MatchFilter.args()...)
+
+	if options.Comments {
+		cmd.Args = append(cmd.Args, options.CommentOptions.args()...)
+	}
+
+	if options.DownloadSubtitles {
+		// validate languages before running youtube-dl
+		if _, err := matchSubtitleLanguages(options.SubtitleLanguages, nil); err != nil {
+			return Info{}, nil, err
+		}
+		cmd.Args = append(cmd.Args, options.subtitleArgs()...)
+	}
+
 	tempPath, _ := os.MkdirTemp("", "ydls")
 	defer os.RemoveAll(tempPath)
 
@@ -442,79 +535,67 @@ func infoFromURL(
 		return Info{}, nil, fmt.Errorf("unknown error")
 	}
 
-	get := func(url string) (*http.Response, error) {
-		c := http.DefaultClient
-		if options.HTTPClient != nil {
-			c = options.HTTPClient
-		}
-
-		r, err := http.NewRequest(http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-		for k, v := range info.HTTPHeaders {
-			r.Header.Set(k, v)
-		}
-		return c.Do(r)
-	}
-
-	if options.DownloadThumbnail && info.Thumbnail != "" {
-		resp, respErr := get(info.Thumbnail)
-		if respErr == nil {
-			buf, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			info.ThumbnailBytes = buf
+	for language, subtitles := range info.Subtitles {
+		for i := range subtitles {
+			subtitles[i].Language = language
 		}
 	}
-
-	for language, subtitles := range info.Subtitles {
+	for language, subtitles := range info.AutomaticCaptions {
 		for i := range subtitles {
 			subtitles[i].Language = language
+			subtitles[i].Automatic = true
 		}
 	}
 
-	if options.DownloadSubtitles {
-		for _, subtitles := range info.Subtitles {
-			for i, subtitle := range subtitles {
-				resp, respErr := get(subtitle.URL)
-				if respErr == nil {
-					buf, _ := io.ReadAll(resp.Body)
-					resp.Body.Close()
-					subtitles[i].Bytes = buf
-				}
+	if options.Type == TypePlaylist || options.Type == TypeChannel {
+		info.Entries = flattenEntries(info.Entries)
+	}
+	if options.Type == TypeChannel {
+		// single tab URL, ex: ".../@name/videos", has entries not nested in tabs
+		tab := channelTabFromURL(info.WebpageURL)
+		for i := range info.Entries {
+			if info.Entries[i].ChannelTab == "" {
+				info.Entries[i].ChannelTab = tab
 			}
 		}
 	}
+	splitStoryboards(&info, options.IncludeStoryboards)
+	if info.Type == "playlist" || info.Type == "multi_video" {
+		info.Entries = options.MatchFilter.filterEntries(info.Entries)
+	}
 
-	// as we ignore errors for playlists some entries might show up as null
-	//
-	// note: instead of doing full recursion, we assume entries in
-	// playlists and channels are at most 2 levels deep, and we just
-	// collect entries from both levels.
-	//
-	// the following cases have not been tested:
-	//
-	// - entries that are more than 2 levels deep (will be missed)
-	// - the ability to restrict entries to a single level (we include both levels)
-	if options.Type == TypePlaylist || options.Type == TypeChannel {
-		var filteredEntries []Info
-		for _, e := range info.Entries {
-			if e.Type == "playlist" {
-				for _, ee := range e.Entries {
-					if ee.ID == "" {
-						continue
-					}
-					filteredEntries = append(filteredEntries, ee)
+	return info, stdoutBuf.Bytes(), nil
+}
+
+// as we ignore errors for playlists some entries might show up as null
+//
+// note: instead of doing full recursion, we assume entries in
+// playlists and channels are at most 2 levels deep, and we just
+// collect entries from both levels.
+//
+// the following cases have not been tested:
+//
+// - entries that are more than 2 levels deep (will be missed)
+// - the ability to restrict entries to a single level (we include both levels)
+func flattenEntries(entries []Info) []Info {
+	var filteredEntries []Info
+	for _, e := range entries {
+		if e.Type == "playlist" {
+			// nested playlists are usually channel tabs
+			tab := channelTabFromURL(e.WebpageURL)
+			for _, ee := range e.Entries {
+				if ee.ID == "" {
+					continue
 				}
-				continue
-			} else if e.ID != "" {
-				filteredEntries = append(filteredEntries, e)
+				ee.ChannelTab = tab
+				filteredEntries = append(filteredEntries, ee)
 			}
+			continue
+		} else if e.ID != "" {
+			filteredEntries = append(filteredEntries, e)
 		}
-		info.Entries = filteredEntries
 	}
-
-	return info, stdoutBuf.Bytes(), nil
+	return filteredEntries
 }
 
 // Result metadata for a URL
@@ -523,12 +604,23 @@ type Result struct {
 	RawURL  string
 	RawJSON []byte  // saved raw JSON. Used later when downloading
 	Options Options // options passed to New
+	// Errors fetching thumbnail and subtitles, asset is left empty on error
+	AssetErrors []AssetError
+
+	// raw JSON for entries resolved by Hydrate
+	hydratedJSON map[string]json.RawMessage
 }
 
 // DownloadResult download result
 type DownloadResult struct {
-	reader io.ReadCloser
-	waitCh chan struct{}
+	reader  io.ReadCloser
+	waitCh  chan struct{}
+	waitErr error
+	eof     bool
+	// called on close if download was read to end and youtube-dl exited ok
+	onComplete func() error
+	// stops youtube-dl on close before end when it's not writing to the reader
+	cancel context.CancelFunc
 }
 
 // Download format matched by filter (usually a format id or quality designator).
@@ -545,29 +637,113 @@ type DownloadOptions struct {
 	DownloadAudioOnly bool   // -x Download audio only from video
 	// Download format matched by filter (usually a format id or quality designator).
 	// If filter is empty, then youtube-dl will use its default format selector.
+	// Filters merging formats, ex: "bestvideo+bestaudio", are downloaded and
+	// merged to a temp file using MergeOutputFormat and then streamed.
 	Filter string
 	// The index of the entry to download from the playlist that would be
-	// passed to youtube-dl via --playlist-items. The index value starts at 1
+	// passed to youtube-dl via --playlist-items. The index value starts at 1.
+	// Matched against playlist_index of the entries, an error is returned if
+	// more than one entry matches, ex: a channel with multiple tabs.
 	PlaylistIndex int
+	// Playlist entries to select, same as PlaylistIndex indexes are matched
+	// against playlist_index of the entries (--playlist-items).
+	// DownloadWithOptions requires exactly one entry to be selected, use
+	// DownloadPlaylist to download multiple entries.
+	PlaylistItems PlaylistItems
+	// Reverse or shuffle order of selected entries. Only used by
+	// DownloadPlaylist and DownloadAll.
+	PlaylistReverse bool
+	PlaylistRandom  bool
+	// Sections to download, validated against Info.Duration if known
+	Sections             []Section // --download-sections
+	ForceKeyframesAtCuts bool      // --force-keyframes-at-cuts
+	// Used by DownloadPlaylist and DownloadAll to skip already downloaded
+	// entries. Entries are added when fully downloaded.
+	Archive ArchiveStore
+	// Stop at first entry found in Archive, useful for incremental syncs of
+	// playlists and channels with newest entries first (--break-on-existing)
+	BreakOnExisting bool
 }
 
 func (result Result) DownloadWithOptions(
 	ctx context.Context,
 	options DownloadOptions,
 ) (*DownloadResult, error) {
-	debugLog := result.Options.DebugLog
+	info, infoJSON, playlistIndex, err := result.downloadTarget(options)
+	if err != nil {
+		return nil, err
+	}
+	return result.download(ctx, info, infoJSON, playlistIndex, options)
+}
+
+// downloadTarget returns info, info JSON and playlist index to download for
+// playlist index and items options
+func (result Result) downloadTarget(options DownloadOptions) (Info, []byte, int, error) {
+	var entry *Info
+	playlistIndex := options.PlaylistIndex
+	if len(options.PlaylistItems) > 0 || playlistIndex > 0 {
+		if len(options.PlaylistItems) > 0 && playlistIndex != 0 {
+			return Info{}, nil, 0, fmt.Errorf("playlist index and items options can't be used together")
+		}
+		items := options.PlaylistItems
+		if playlistIndex > 0 {
+			items = PlaylistItems{PlaylistItem(playlistIndex)}
+		}
+		selectOptions := options
+		selectOptions.PlaylistItems = items
+		entries := result.selectedEntries(selectOptions)
+		switch {
+		case len(entries) == 1:
+			entry = &entries[0]
+			playlistIndex = int(entry.PlaylistIndex)
+		case len(entries) > 1:
+			// ex: flattened channel tabs each have their own playlist indexes
+			return Info{}, nil, 0, fmt.Errorf(
+				"playlist items %s selects %d entries, expected one, use DownloadEntry", items, len(entries),
+			)
+		case len(options.PlaylistItems) > 0:
+			return Info{}, nil, 0, fmt.Errorf("playlist items %s selects no entries", items)
+		}
+	}
 
 	if !result.Options.noInfoDownload {
+		// load only the selected entry info instead of the whole playlist,
+		// fallback to --playlist-items if entry is not found
+		if entry != nil {
+			if raw, err := result.rawEntry(entry.ID); err == nil {
+				return *entry, raw, 0, nil
+			}
+		}
+
 		if (result.Info.Type == "playlist" ||
 			result.Info.Type == "multi_video" ||
 			result.Info.Type == "channel") &&
-			options.PlaylistIndex == 0 {
-			return nil, fmt.Errorf(
+			playlistIndex == 0 {
+			return Info{}, nil, 0, fmt.Errorf(
 				"can't download a playlist when the playlist index options is not set",
 			)
 		}
 	}
 
+	return result.Info, result.RawJSON, playlistIndex, nil
+}
+
+// download info using infoJSON passed to youtube-dl via --load-info
+func (result Result) download(
+	ctx context.Context,
+	info Info,
+	infoJSON []byte,
+	playlistIndex int,
+	options DownloadOptions,
+) (*DownloadResult, error) {
+	debugLog := result.Options.DebugLog
+
+	for _, s := range options.Sections {
+		if err := s.Validate(info.Duration); err != nil {
+			return nil, err
+		}
+	}
+
 	tempPath, tempErr := os.MkdirTemp("", "ydls")
 	if tempErr != nil {
 		return nil, tempErr
@@ -576,7 +752,7 @@ func (result Result) DownloadWithOptions(
 	var jsonTempPath string
 	if !result.Options.noInfoDownload {
 		jsonTempPath = path.Join(tempPath, "info.json")
-		if err := os.WriteFile(jsonTempPath, result.RawJSON, 0600); err != nil {
+		if err := os.WriteFile(jsonTempPath, infoJSON, 0600); err != nil {
 			os.RemoveAll(tempPath)
 			return nil, err
 		}
@@ -586,6 +762,108 @@ func (result Result) DownloadWithOptions(
 		waitCh: make(chan struct{}),
 	}
 
+	merge := filterRequiresMerge(options.Filter)
+	output := "-"
+	if merge {
+		// youtube-dl can't merge when writing to stdout, merge to a file in
+		// temp dir and stream it when done
+		output = path.Join(tempPath, mergedOutputName+".%(ext)s")
+		// closing the reader won't stop youtube-dl, Close cancels instead
+		ctx, dr.cancel = context.WithCancel(ctx)
+	}
+	cmd := result.downloadCmd(ctx, info, jsonTempPath, playlistIndex, output, options)
+
+	cmd.Dir = tempPath
+	var stdoutW *io.PipeWriter
+	var stderrW io.WriteCloser
+	var stderrR io.Reader
+	dr.reader, stdoutW = io.Pipe()
+	stderrR, stderrW = io.Pipe()
+	optStderrWriter := io.Discard
+	if result.Options.StderrFn != nil {
+		optStderrWriter = result.Options.StderrFn(cmd)
+	}
+	cmd.Stdout = stdoutW
+	cmd.Stderr = io.MultiWriter(optStderrWriter, stderrW)
+	if merge {
+		// progress is written to stdout when not downloading to stdout,
+		// same writer so that writes are not concurrent
+		cmd.Stdout = cmd.Stderr
+	}
+
+	debugLog.Print("cmd", " ", cmd.Args)
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(tempPath)
+		return nil, err
+	}
+
+	// blocks return until yt-dlp is downloading or has errored, keeps reading
+	// stderr after that to know last error if yt-dlp exits with failure
+	ytErrCh := make(chan error, 1)
+	stderrDoneCh := make(chan struct{})
+	var lastErr string
+	go func() {
+		defer close(stderrDoneCh)
+		started := false
+		stderrLineScanner := bufio.NewScanner(stderrR)
+		for stderrLineScanner.Scan() {
+			const downloadPrefix = "[download]"
+			const errorPrefix = "ERROR: "
+			line := stderrLineScanner.Text()
+			if strings.HasPrefix(line, downloadPrefix) && !started {
+				started = true
+				ytErrCh <- nil
+			} else if strings.HasPrefix(line, errorPrefix) {
+				lastErr = line[len(errorPrefix):]
+				if !started {
+					started = true
+					ytErrCh <- errors.New(lastErr)
+				}
+			}
+		}
+		if !started {
+			ytErrCh <- nil
+		}
+		_, _ = io.Copy(io.Discard, stderrR)
+	}()
+
+	go func() {
+		err := cmd.Wait()
+		if !merge {
+			stdoutW.Close()
+		}
+		stderrW.Close()
+		<-stderrDoneCh
+		if err != nil && lastErr != "" {
+			err = YoutubedlError(lastErr)
+		}
+		if merge {
+			if err == nil {
+				err = copyMergedFile(stdoutW, tempPath)
+			}
+			stdoutW.Close()
+		}
+		dr.waitErr = err
+		os.RemoveAll(tempPath)
+		if dr.cancel != nil {
+			dr.cancel()
+		}
+		close(dr.waitCh)
+	}()
+
+	return dr, <-ytErrCh
+}
+
+// downloadCmd returns youtube-dl command downloading info to output, "-" is stdout.
+// jsonTempPath is info JSON file passed via --load-info.
+func (result Result) downloadCmd(
+	ctx context.Context,
+	info Info,
+	jsonTempPath string,
+	playlistIndex int,
+	output string,
+	options DownloadOptions,
+) *exec.Cmd {
 	cmd := exec.CommandContext(
 		ctx,
 		ProbePath(),
@@ -600,8 +878,8 @@ func (result Result) DownloadWithOptions(
 		"--restrict-filenames",
 		// use .netrc authentication data
 		"--netrc",
-		// write to stdout
-		"--output", "-",
+		// write to stdout or file
+		"--output", output,
 	)
 
 	if result.Options.noInfoDownload {
@@ -627,6 +905,13 @@ func (result Result) DownloadWithOptions(
 				"--no-playlist",
 			)
 		}
+
+		cmd.Args = append(cmd.Args, result.Options.MatchFilter.args()...)
+		if result.Options.MatchFilter.MaxDownloads > 0 {
+			cmd.Args = append(cmd.Args,
+				"--max-downloads", strconv.Itoa(int(result.Options.MatchFilter.MaxDownloads)),
+			)
+		}
 	} else {
 		cmd.Args = append(cmd.Args, "--load-info", jsonTempPath)
 	}
@@ -636,12 +921,12 @@ func (result Result) DownloadWithOptions(
 	}
 	// don't need to specify if direct as there is only one
 	// also seems to be issues when using filter with generic extractor
-	if !result.Info.Direct && options.Filter != "" {
+	if !info.Direct && options.Filter != "" {
 		cmd.Args = append(cmd.Args, "-f", options.Filter)
 	}
 
-	if options.PlaylistIndex > 0 {
-		cmd.Args = append(cmd.Args, "--playlist-items", fmt.Sprint(options.PlaylistIndex))
+	if playlistIndex > 0 {
+		cmd.Args = append(cmd.Args, "--playlist-items", fmt.Sprint(playlistIndex))
 	}
 
 	if options.DownloadAudioOnly {
@@ -664,6 +949,14 @@ func (result Result) DownloadWithOptions(
 		cmd.Args = append(cmd.Args, "--download-sections", result.Options.DownloadSections)
 	}
 
+	for _, s := range options.Sections {
+		cmd.Args = append(cmd.Args, "--download-sections", s.String())
+	}
+
+	if options.ForceKeyframesAtCuts {
+		cmd.Args = append(cmd.Args, "--force-keyframes-at-cuts")
+	}
+
 	if result.Options.CookiesFromBrowser != "" {
 		cmd.Args = append(cmd.Args, "--cookies-from-browser", result.Options.CookiesFromBrowser)
 	}
@@ -680,66 +973,39 @@ func (result Result) DownloadWithOptions(
 		)
 	}
 
-	cmd.Dir = tempPath
-	var stdoutW io.WriteCloser
-	var stderrW io.WriteCloser
-	var stderrR io.Reader
-	dr.reader, stdoutW = io.Pipe()
-	stderrR, stderrW = io.Pipe()
-	optStderrWriter := io.Discard
-	if result.Options.StderrFn != nil {
-		optStderrWriter = result.Options.StderrFn(cmd)
-	}
-	cmd.Stdout = stdoutW
-	cmd.Stderr = io.MultiWriter(optStderrWriter, stderrW)
-
-	debugLog.Print("cmd", " ", cmd.Args)
-	if err := cmd.Start(); err != nil {
-		os.RemoveAll(tempPath)
-		return nil, err
-	}
-
-	go func() {
-		_ = cmd.Wait()
-		stdoutW.Close()
-		stderrW.Close()
-		os.RemoveAll(tempPath)
-		close(dr.waitCh)
-	}()
-
-	// blocks return until yt-dlp is downloading or has errored
-	ytErrCh := make(chan error)
-	go func() {
-		stderrLineScanner := bufio.NewScanner(stderrR)
-		for stderrLineScanner.Scan() {
-			const downloadPrefix = "[download]"
-			const errorPrefix = "ERROR: "
-			line := stderrLineScanner.Text()
-			if strings.HasPrefix(line, downloadPrefix) {
-				break
-			} else if strings.HasPrefix(line, errorPrefix) {
-				ytErrCh <- errors.New(line[len(errorPrefix):])
-				return
-			}
-		}
-		ytErrCh <- nil
-		_, _ = io.Copy(io.Discard, stderrR)
-	}()
-
-	return dr, <-ytErrCh
+	return cmd
 }
 
 func (dr *DownloadResult) Read(p []byte) (n int, err error) {
-	return dr.reader.Read(p)
+	n, err = dr.reader.Read(p)
+	if err == io.EOF {
+		dr.eof = true
+	}
+	return n, err
 }
 
 // Close downloader and wait for resource cleanup
 func (dr *DownloadResult) Close() error {
 	err := dr.reader.Close()
+	if dr.cancel != nil && !dr.eof {
+		dr.cancel()
+	}
 	<-dr.waitCh
+	if err == nil && dr.eof && dr.waitErr == nil && dr.onComplete != nil {
+		err = dr.onComplete()
+	}
 	return err
 }
 
+// exitErr returns youtube-dl exit error if download was read to end, closing
+// before end is not an error. Only valid after Close.
+func (dr *DownloadResult) exitErr() error {
+	if !dr.eof {
+		return nil
+	}
+	return dr.waitErr
+}
+
 // Formats return all formats
 // helper to take care of mixed info and format
 func (result Result) Formats() []Format {
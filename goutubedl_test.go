@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -586,3 +587,259 @@ func TestFormatDownloadError(t *testing.T) {
 
 	}
 }
+
+func TestDownloadPlaylist(t *testing.T) {
+	defer leakChecks(t)()
+
+	r, err := goutubedl.New(context.Background(), playlistRawURL, goutubedl.Options{
+		Type: goutubedl.TypePlaylist,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pd, err := r.DownloadPlaylist(goutubedl.DownloadOptions{
+		PlaylistItems:   goutubedl.PlaylistItems{{Start: 2, End: 3}},
+		PlaylistReverse: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTitles := []string{r.Info.Entries[2].Title, r.Info.Entries[1].Title}
+	var titles []string
+	for {
+		info, dr, err := pd.Next(context.Background())
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		n, err := io.Copy(io.Discard, dr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dr.Close()
+		if n < 10000 {
+			t.Errorf("should have copied at least 10000 bytes: %d", n)
+		}
+		titles = append(titles, info.Title)
+	}
+
+	if strings.Join(titles, ",") != strings.Join(expectedTitles, ",") {
+		t.Errorf("expected titles %q got %q", expectedTitles, titles)
+	}
+}
+
+func TestDownloadEntry(t *testing.T) {
+	defer leakChecks(t)()
+
+	r, err := goutubedl.New(context.Background(), playlistRawURL, goutubedl.Options{
+		Type: goutubedl.TypePlaylist,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := r.Info.Entries[1]
+	dr, err := r.DownloadEntry(context.Background(), entry.ID, goutubedl.DownloadOptions{
+		Filter: entry.Formats[0].FormatID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := io.Copy(io.Discard, dr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dr.Close()
+
+	if n < 10000 {
+		t.Errorf("should have copied at least 10000 bytes: %d", n)
+	}
+
+	if _, err := r.DownloadEntry(context.Background(), "non-existing", goutubedl.DownloadOptions{}); !errors.Is(err, goutubedl.ErrEntryNotFound) {
+		t.Errorf("expected entry not found error, got %v", err)
+	}
+}
+
+func TestDownloadAll(t *testing.T) {
+	defer leakChecks(t)()
+
+	r, err := goutubedl.New(context.Background(), playlistRawURL, goutubedl.Options{
+		Type: goutubedl.TypePlaylist,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	skipID := r.Info.Entries[0].ID
+	summary, err := r.DownloadAll(context.Background(), goutubedl.DownloadAllOptions{
+		DownloadOptions: goutubedl.DownloadOptions{
+			PlaylistItems: goutubedl.PlaylistItems{{Start: 1, End: 3}},
+		},
+		Retries: 1,
+		Skip:    func(info goutubedl.Info) bool { return info.ID == skipID },
+		Fn: func(info goutubedl.Info, dr *goutubedl.DownloadResult) error {
+			n, err := io.Copy(io.Discard, dr)
+			if err != nil {
+				return err
+			}
+			if n < 10000 {
+				return fmt.Errorf("should have copied at least 10000 bytes: %d", n)
+			}
+			return nil
+		},
+	}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Succeeded) != 2 || len(summary.Skipped) != 1 || len(summary.Failed) != 0 {
+		t.Errorf("expected 2 succeeded, 1 skipped and 0 failed, got %d %d %v",
+			len(summary.Succeeded), len(summary.Skipped), summary.Failed)
+	}
+}
+
+func TestHydrate(t *testing.T) {
+	defer leakChecks(t)()
+
+	r, err := goutubedl.New(context.Background(), playlistRawURL, goutubedl.Options{
+		Type:          goutubedl.TypePlaylist,
+		FlatPlaylist:  true,
+		PlaylistStart: 1,
+		PlaylistEnd:   2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range r.Info.Entries {
+		if !e.IsFlat() {
+			t.Errorf("expected flat entry got type %q", e.Type)
+		}
+	}
+
+	if errs := r.Hydrate(context.Background(), 2); len(errs) != 0 {
+		t.Fatalf("expected no errors got %v", errs)
+	}
+
+	expectedTitleOne := "A1 Mattheis - Herds"
+	if r.Info.Entries[0].Title != expectedTitleOne {
+		t.Errorf("expected title %q got %q", expectedTitleOne, r.Info.Entries[0].Title)
+	}
+	for _, e := range r.Info.Entries {
+		if e.IsFlat() || len(e.Formats) == 0 {
+			t.Errorf("expected hydrated entry with formats got type %q", e.Type)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	defer leakChecks(t)()
+
+	r, err := goutubedl.Search(context.Background(), goutubedl.SearchSoundCloud, "mattheis", 3, goutubedl.SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Info.Entries) != 3 {
+		t.Fatalf("expected 3 entries got %d", len(r.Info.Entries))
+	}
+
+	next, err := goutubedl.Search(context.Background(), goutubedl.SearchSoundCloud, "mattheis", 3, goutubedl.SearchOptions{
+		Offset: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Info.Entries) != 3 {
+		t.Fatalf("expected 3 entries got %d", len(next.Info.Entries))
+	}
+	for _, e := range next.Info.Entries {
+		if e.ID == r.Info.Entries[0].ID {
+			t.Errorf("expected next page to not contain %s", e.ID)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	defer leakChecks(t)()
+
+	for _, c := range []struct {
+		url          string
+		expectedType goutubedl.Type
+		expectedErr  error
+	}{
+		{testVideoRawURL, goutubedl.TypeSingle, goutubedl.ErrNotAPlaylist},
+		{playlistRawURL, goutubedl.TypePlaylist, goutubedl.ErrNotASingleEntry},
+	} {
+		t.Run(c.url, func(t *testing.T) {
+			d, err := goutubedl.Detect(context.Background(), c.url, goutubedl.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Type != c.expectedType {
+				t.Errorf("expected type %d got %d", c.expectedType, d.Type)
+			}
+			if d.ID == "" || d.ExtractorKey == "" {
+				t.Errorf("expected id and extractor key got %q %q", d.ID, d.ExtractorKey)
+			}
+			if err := d.Expect(goutubedl.TypeAny); err != nil {
+				t.Errorf("expected no error for any type got %s", err)
+			}
+			expectType := goutubedl.TypePlaylist
+			if c.expectedType != goutubedl.TypeSingle {
+				expectType = goutubedl.TypeSingle
+			}
+			if err := d.Expect(expectType); err != c.expectedErr {
+				t.Errorf("expected error %v got %v", c.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestListExtractors(t *testing.T) {
+	defer leakChecks(t)()
+
+	es, err := goutubedl.ListExtractors(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, e := range es {
+		if e.Name == "youtube" {
+			found = true
+			if e.Description == "" {
+				t.Errorf("expected youtube extractor description")
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected to find youtube extractor")
+	}
+}
+
+func TestSupportsURL(t *testing.T) {
+	defer leakChecks(t)()
+
+	for _, c := range []struct {
+		url               string
+		expectedExtractor string
+		expectedSupported bool
+	}{
+		{"https://www.youtube.com/watch?v=jgVhBThJdXc", "youtube", true},
+		{testVideoRawURL, "media.ccc.de", true},
+		{"https://www.google.com", "generic", false},
+	} {
+		t.Run(c.url, func(t *testing.T) {
+			extractor, supported, err := goutubedl.SupportsURL(context.Background(), c.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if extractor != c.expectedExtractor || supported != c.expectedSupported {
+				t.Errorf("expected %q %v got %q %v", c.expectedExtractor, c.expectedSupported, extractor, supported)
+			}
+		})
+	}
+}
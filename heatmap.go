@@ -0,0 +1,23 @@
+package goutubedl
+
+// HeatmapSegment youtube-dl heatmap segment, how often a part of a video is
+// replayed
+type HeatmapSegment struct {
+	StartTime float64 `json:"start_time"` // Start in seconds
+	EndTime   float64 `json:"end_time"`   // End in seconds
+	Value     float64 `json:"value"`      // Normalized intensity, 0 to 1
+}
+
+// MostReplayed returns heatmap segment with highest value, false if there is
+// no heatmap
+func (info Info) MostReplayed() (HeatmapSegment, bool) {
+	var best HeatmapSegment
+	found := false
+	for _, s := range info.Heatmap {
+		if !found || s.Value > best.Value {
+			best = s
+			found = true
+		}
+	}
+	return best, found
+}
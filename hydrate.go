@@ -0,0 +1,129 @@
+package goutubedl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// IsFlat returns true if info is a flat playlist entry that only has basic
+// info like id, url and title, see Options.FlatPlaylist
+func (info Info) IsFlat() bool {
+	return info.Type == "url" || info.Type == "url_transparent"
+}
+
+// ResolveEntry fetches full info for a flat playlist entry using the same
+// options as New, ex: cookies and proxy.
+// Playlist fields missing in the full info are copied from entry.
+func (result Result) ResolveEntry(ctx context.Context, entry Info) (Result, error) {
+	rawURL := entry.URL
+	if rawURL == "" {
+		rawURL = entry.WebpageURL
+	}
+	if rawURL == "" {
+		return Result{}, errors.New("entry has no URL")
+	}
+
+	options := result.Options
+	options.Type = TypeAny
+	options.FlatPlaylist = false
+	options.noInfoDownload = false
+	// start and end are for the playlist, not the entry
+	options.PlaylistStart = 0
+	options.PlaylistEnd = 0
+
+	r, err := New(ctx, rawURL, options)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if r.Info.PlaylistIndex == 0 {
+		r.Info.PlaylistIndex = entry.PlaylistIndex
+	}
+	if r.Info.Playlist == "" {
+		r.Info.Playlist = entry.Playlist
+	}
+	if r.Info.PlaylistID == "" {
+		r.Info.PlaylistID = entry.PlaylistID
+	}
+	if r.Info.PlaylistTitle == "" {
+		r.Info.PlaylistTitle = entry.PlaylistTitle
+	}
+	if r.Info.PlaylistUploader == "" {
+		r.Info.PlaylistUploader = entry.PlaylistUploader
+	}
+	if r.Info.PlaylistUploaderID == "" {
+		r.Info.PlaylistUploaderID = entry.PlaylistUploaderID
+	}
+
+	return r, nil
+}
+
+// Hydrate resolves flat entries in Info.Entries to full info using
+// concurrency number of workers, see ResolveEntry. Resolved entries replace
+// the flat entries and are used for following entry downloads.
+// Returns errors for entries that failed to resolve, they are kept flat. If ctx
+// is cancelled entries not yet started are returned with the ctx error.
+// Info.Entries is replaced with a new slice and the full info JSON is only
+// stored in result, so copies of result made before calling Hydrate keep
+// their flat entries.
+func (result *Result) Hydrate(ctx context.Context, concurrency int) []EntryError {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	result.Info.Entries = append([]Info(nil), result.Info.Entries...)
+	hydratedJSON := map[string]json.RawMessage{}
+	for k, v := range result.hydratedJSON {
+		hydratedJSON[k] = v
+	}
+	result.hydratedJSON = hydratedJSON
+
+	var mu sync.Mutex
+	var errs []EntryError
+	indexCh := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				entry := result.Info.Entries[i]
+				r, err := result.ResolveEntry(ctx, entry)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, EntryError{Info: entry, Err: err})
+				} else {
+					result.Info.Entries[i] = r.Info
+					result.hydratedJSON[r.Info.ID] = r.RawJSON
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var notStarted []Info
+	for i, e := range result.Info.Entries {
+		if !e.IsFlat() {
+			continue
+		}
+		if ctx.Err() != nil {
+			notStarted = append(notStarted, e)
+			continue
+		}
+		select {
+		case indexCh <- i:
+		case <-ctx.Done():
+			notStarted = append(notStarted, e)
+		}
+	}
+	close(indexCh)
+	wg.Wait()
+
+	for _, e := range notStarted {
+		errs = append(errs, EntryError{Info: e, Err: ctx.Err()})
+	}
+
+	return errs
+}
@@ -0,0 +1,76 @@
+package goutubedl_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestHydrateFake(t *testing.T) {
+	useFake(t)
+
+	result, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Type:          goutubedl.TypePlaylist,
+		FlatPlaylist:  true,
+		PlaylistStart: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flatResult := result
+
+	if errs := result.Hydrate(context.Background(), 2); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	for _, e := range result.Info.Entries {
+		if e.IsFlat() || len(e.Formats) == 0 {
+			t.Errorf("expected %s to be hydrated", e.ID)
+		}
+		if e.PlaylistIndex == 0 {
+			t.Errorf("expected %s to keep playlist index", e.ID)
+		}
+	}
+	for _, e := range flatResult.Info.Entries {
+		if !e.IsFlat() {
+			t.Errorf("expected %s in copy made before hydrate to be flat", e.ID)
+		}
+	}
+
+	dr, err := result.DownloadEntry(context.Background(), "e3", goutubedl.DownloadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloaded, err := readFakeDownload(t, dr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downloaded.ID != "e3" || len(downloaded.Formats) == 0 {
+		t.Errorf("expected hydrated e3 to be downloaded got %s", downloaded.ID)
+	}
+}
+
+func TestHydrateCancelFake(t *testing.T) {
+	useFake(t)
+
+	result, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Type:         goutubedl.TypePlaylist,
+		FlatPlaylist: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	cancelFn()
+	errs := result.Hydrate(ctx, 1)
+	if len(errs) != len(result.Info.Entries) {
+		t.Fatalf("expected %d errors got %d", len(result.Info.Entries), len(errs))
+	}
+	for _, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %s to be cancelled got %s", err.Info.ID, err.Err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package livechat reads YouTube live chat replay as written by youtube-dl for
+// the "live_chat" subtitle, one JSON object per line.
+//
+// The replay is not fetched by goutubedl.New, it has to be written to a file
+// by youtube-dl, ex: using Result.DownloadToDir with WriteSubtitles and
+// Options.SubtitleLanguages "live_chat". Open the FileRoleSubtitle file ending
+// with ".live_chat.json" and pass it to NewReader.
+package livechat
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MessageType type of chat message
+type MessageType string
+
+// Message types
+const (
+	TypeText         MessageType = "text"
+	TypeSuperChat    MessageType = "superchat"
+	TypeSuperSticker MessageType = "supersticker"
+	TypeMembership   MessageType = "membership"
+)
+
+// Message chat message
+type Message struct {
+	ID   string
+	Type MessageType
+	// Offset from start of video, negative for messages before stream start
+	Offset          time.Duration
+	Timestamp       time.Time
+	AuthorName      string
+	AuthorChannelID string
+	// Text with emojis as unicode or custom emoji shortcut, ex: ":yt:"
+	Text string
+	// Badge tooltips, ex: "Moderator", "Member (6 months)"
+	Badges []string
+	// Formatted amount for super chats and stickers, ex: "$5.00"
+	SuperChatAmount string
+}
+
+type text struct {
+	SimpleText string `json:"simpleText"`
+	Runs       []struct {
+		Text  string `json:"text"`
+		Emoji *struct {
+			EmojiID       string   `json:"emojiId"`
+			Shortcuts     []string `json:"shortcuts"`
+			IsCustomEmoji bool     `json:"isCustomEmoji"`
+		} `json:"emoji"`
+	} `json:"runs"`
+}
+
+func (t text) String() string {
+	if t.SimpleText != "" {
+		return t.SimpleText
+	}
+	var sb strings.Builder
+	for _, r := range t.Runs {
+		switch {
+		case r.Emoji == nil:
+			sb.WriteString(r.Text)
+		case r.Emoji.IsCustomEmoji && len(r.Emoji.Shortcuts) > 0:
+			sb.WriteString(r.Emoji.Shortcuts[0])
+		default:
+			sb.WriteString(r.Emoji.EmojiID)
+		}
+	}
+	return sb.String()
+}
+
+type renderer struct {
+	ID                      string `json:"id"`
+	TimestampUsec           string `json:"timestampUsec"`
+	AuthorName              text   `json:"authorName"`
+	AuthorExternalChannelID string `json:"authorExternalChannelId"`
+	Message                 text   `json:"message"`
+	HeaderSubtext           text   `json:"headerSubtext"`
+	PurchaseAmountText      text   `json:"purchaseAmountText"`
+	AuthorBadges            []struct {
+		LiveChatAuthorBadgeRenderer struct {
+			Tooltip string `json:"tooltip"`
+		} `json:"liveChatAuthorBadgeRenderer"`
+	} `json:"authorBadges"`
+}
+
+type line struct {
+	ReplayChatItemAction struct {
+		VideoOffsetTimeMsec string `json:"videoOffsetTimeMsec"`
+		Actions             []struct {
+			AddChatItemAction *struct {
+				Item struct {
+					LiveChatTextMessageRenderer    *renderer `json:"liveChatTextMessageRenderer"`
+					LiveChatPaidMessageRenderer    *renderer `json:"liveChatPaidMessageRenderer"`
+					LiveChatPaidStickerRenderer    *renderer `json:"liveChatPaidStickerRenderer"`
+					LiveChatMembershipItemRenderer *renderer `json:"liveChatMembershipItemRenderer"`
+				} `json:"item"`
+			} `json:"addChatItemAction"`
+		} `json:"actions"`
+	} `json:"replayChatItemAction"`
+}
+
+// Reader reads messages one line at a time so that long chats don't have to
+// fit in memory
+type Reader struct {
+	r       *bufio.Reader
+	lineNr  int
+	pending []Message
+}
+
+// NewReader returns a Reader reading live chat JSON lines from r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns next message. Returns io.EOF when there are no more messages.
+// Actions that are not messages, ex: deleted messages and tickers, are skipped.
+func (r *Reader) Next() (Message, error) {
+	for len(r.pending) == 0 {
+		b, err := r.r.ReadBytes('\n')
+		if len(b) == 0 && err != nil {
+			return Message{}, err
+		}
+		r.lineNr++
+		if err := r.parseLine(b); err != nil {
+			return Message{}, fmt.Errorf("live chat line %d: %w", r.lineNr, err)
+		}
+	}
+
+	m := r.pending[0]
+	r.pending = r.pending[1:]
+	return m, nil
+}
+
+func (r *Reader) parseLine(b []byte) error {
+	if strings.TrimSpace(string(b)) == "" {
+		return nil
+	}
+	var l line
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+
+	var offset time.Duration
+	if s := l.ReplayChatItemAction.VideoOffsetTimeMsec; s != "" {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid offset %q", s)
+		}
+		offset = time.Duration(ms) * time.Millisecond
+	}
+
+	for _, a := range l.ReplayChatItemAction.Actions {
+		if a.AddChatItemAction == nil {
+			continue
+		}
+		item := a.AddChatItemAction.Item
+		var rr *renderer
+		var typ MessageType
+		switch {
+		case item.LiveChatTextMessageRenderer != nil:
+			rr, typ = item.LiveChatTextMessageRenderer, TypeText
+		case item.LiveChatPaidMessageRenderer != nil:
+			rr, typ = item.LiveChatPaidMessageRenderer, TypeSuperChat
+		case item.LiveChatPaidStickerRenderer != nil:
+			rr, typ = item.LiveChatPaidStickerRenderer, TypeSuperSticker
+		case item.LiveChatMembershipItemRenderer != nil:
+			rr, typ = item.LiveChatMembershipItemRenderer, TypeMembership
+		default:
+			continue
+		}
+
+		m := Message{
+			ID:              rr.ID,
+			Type:            typ,
+			Offset:          offset,
+			AuthorName:      rr.AuthorName.String(),
+			AuthorChannelID: rr.AuthorExternalChannelID,
+			Text:            rr.Message.String(),
+			SuperChatAmount: rr.PurchaseAmountText.String(),
+		}
+		if m.Text == "" && typ == TypeMembership {
+			m.Text = rr.HeaderSubtext.String()
+		}
+		if rr.TimestampUsec != "" {
+			usec, err := strconv.ParseInt(rr.TimestampUsec, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid timestamp %q", rr.TimestampUsec)
+			}
+			m.Timestamp = time.Unix(0, usec*1000).UTC()
+		}
+		for _, b := range rr.AuthorBadges {
+			if t := b.LiveChatAuthorBadgeRenderer.Tooltip; t != "" {
+				m.Badges = append(m.Badges, t)
+			}
+		}
+		r.pending = append(r.pending, m)
+	}
+
+	return nil
+}
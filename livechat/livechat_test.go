@@ -0,0 +1,72 @@
+package livechat_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl/livechat"
+)
+
+const testChat = `{"replayChatItemAction": {"actions": [{"addChatItemAction": {"item": {"liveChatTextMessageRenderer": {"message": {"runs": [{"text": "hello "}, {"emoji": {"emojiId": "😀", "shortcuts": [":grinning:"]}}, {"emoji": {"emojiId": "UCx/abc", "shortcuts": [":yt:"], "isCustomEmoji": true}}]}, "authorName": {"simpleText": "Alice"}, "authorExternalChannelId": "UCalice", "authorBadges": [{"liveChatAuthorBadgeRenderer": {"tooltip": "Moderator"}}], "id": "m1", "timestampUsec": "1600000000500000"}}}}], "videoOffsetTimeMsec": "1500"}}
+{"replayChatItemAction": {"actions": [{"markChatItemAsDeletedAction": {"targetItemId": "m0"}}, {"addChatItemAction": {"item": {"liveChatPaidMessageRenderer": {"id": "m2", "timestampUsec": "1600000001000000", "authorName": {"simpleText": "Bob"}, "authorExternalChannelId": "UCbob", "purchaseAmountText": {"simpleText": "$5.00"}, "message": {"runs": [{"text": "thanks"}]}}}}}, {"addChatItemAction": {"item": {"liveChatMembershipItemRenderer": {"id": "m3", "authorName": {"simpleText": "Carol"}, "headerSubtext": {"runs": [{"text": "Welcome to "}, {"text": "Members"}]}}}}}], "videoOffsetTimeMsec": "-2000"}}
+
+{"replayChatItemAction": {"actions": [{"addLiveChatTickerItemAction": {}}], "videoOffsetTimeMsec": "3000"}}
+`
+
+func TestReader(t *testing.T) {
+	r := livechat.NewReader(strings.NewReader(testChat))
+
+	var messages []livechat.Message
+	for {
+		m, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, m)
+	}
+
+	expected := []livechat.Message{
+		{
+			ID:              "m1",
+			Type:            livechat.TypeText,
+			Offset:          1500 * time.Millisecond,
+			Timestamp:       time.Date(2020, 9, 13, 12, 26, 40, 500000000, time.UTC),
+			AuthorName:      "Alice",
+			AuthorChannelID: "UCalice",
+			Text:            "hello 😀:yt:",
+			Badges:          []string{"Moderator"},
+		},
+		{
+			ID:              "m2",
+			Type:            livechat.TypeSuperChat,
+			Offset:          -2 * time.Second,
+			Timestamp:       time.Date(2020, 9, 13, 12, 26, 41, 0, time.UTC),
+			AuthorName:      "Bob",
+			AuthorChannelID: "UCbob",
+			Text:            "thanks",
+			SuperChatAmount: "$5.00",
+		},
+		{
+			ID:         "m3",
+			Type:       livechat.TypeMembership,
+			Offset:     -2 * time.Second,
+			AuthorName: "Carol",
+			Text:       "Welcome to Members",
+		},
+	}
+	if !reflect.DeepEqual(expected, messages) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, messages)
+	}
+}
+
+func TestReaderInvalid(t *testing.T) {
+	r := livechat.NewReader(strings.NewReader("{}\n{\n"))
+	if _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected line 2 error got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package goutubedl
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MatchFilter filters playlist entries. Zero values are not used.
+// Note that with FlatPlaylist entries usually lack fields like upload date and
+// view count, youtube-dl then lets them pass.
+type MatchFilter struct {
+	// --match-filters expressions, ex: "!is_live & like_count>?100".
+	// Entries matching any of them are kept.
+	Match       []string
+	DateAfter   time.Time     // --dateafter, uploaded on or after date
+	DateBefore  time.Time     // --datebefore, uploaded on or before date
+	MinViews    uint          // --min-views
+	MaxViews    uint          // --max-views
+	MinDuration time.Duration // added to match filters as "duration>=?"
+	MaxDuration time.Duration // added to match filters as "duration<=?"
+	// Max number of entries to keep. Passed as --max-downloads when downloading
+	// without info, see Download function, otherwise applied to entries.
+	MaxDownloads uint
+	// If not nil entries are only kept if Func returns true. Called after
+	// youtube-dl has done its filtering.
+	Func func(info Info) bool
+}
+
+func formatMatchDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// args returns youtube-dl arguments for filter except --max-downloads
+func (f MatchFilter) args() []string {
+	var args []string
+
+	// multiple --match-filters are OR:ed so typed conditions are AND:ed to each
+	var conds []string
+	if f.MinDuration != 0 {
+		conds = append(conds, "duration>=?"+strconv.FormatFloat(f.MinDuration.Seconds(), 'f', -1, 64))
+	}
+	if f.MaxDuration != 0 {
+		conds = append(conds, "duration<=?"+strconv.FormatFloat(f.MaxDuration.Seconds(), 'f', -1, 64))
+	}
+	if len(f.Match) == 0 && len(conds) > 0 {
+		args = append(args, "--match-filters", strings.Join(conds, " & "))
+	}
+	for _, m := range f.Match {
+		args = append(args, "--match-filters", strings.Join(append([]string{m}, conds...), " & "))
+	}
+
+	if !f.DateAfter.IsZero() {
+		args = append(args, "--dateafter", formatMatchDate(f.DateAfter))
+	}
+	if !f.DateBefore.IsZero() {
+		args = append(args, "--datebefore", formatMatchDate(f.DateBefore))
+	}
+	if f.MinViews != 0 {
+		args = append(args, "--min-views", strconv.Itoa(int(f.MinViews)))
+	}
+	if f.MaxViews != 0 {
+		args = append(args, "--max-views", strconv.Itoa(int(f.MaxViews)))
+	}
+
+	return args
+}
+
+// filterEntries applies Func and MaxDownloads to entries
+func (f MatchFilter) filterEntries(entries []Info) []Info {
+	if f.Func == nil && f.MaxDownloads == 0 {
+		return entries
+	}
+
+	var filtered []Info
+	for _, e := range entries {
+		if f.MaxDownloads != 0 && uint(len(filtered)) >= f.MaxDownloads {
+			break
+		}
+		if f.Func != nil && !f.Func(e) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	return filtered
+}
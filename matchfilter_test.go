@@ -0,0 +1,56 @@
+package goutubedl_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestMatchFilterFake(t *testing.T) {
+	useFake(t)
+
+	result, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Type: goutubedl.TypePlaylist,
+		MatchFilter: goutubedl.MatchFilter{
+			Match:        []string{"!is_live", "like_count>?100"},
+			DateAfter:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			DateBefore:   time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+			MinViews:     10,
+			MinDuration:  5 * time.Minute,
+			MaxDuration:  15 * time.Minute,
+			MaxDownloads: 2,
+			Func:         func(info goutubedl.Info) bool { return info.ID != "e4" },
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := strings.Join(fakeArgs(t, result), " ")
+	for _, expected := range []string{
+		"--match-filters !is_live & duration>=?300 & duration<=?900",
+		"--match-filters like_count>?100 & duration>=?300 & duration<=?900",
+		"--dateafter 20240102",
+		"--datebefore 20240203",
+		"--min-views 10",
+	} {
+		if !strings.Contains(args, expected) {
+			t.Errorf("expected %q in args %q", expected, args)
+		}
+	}
+	if strings.Contains(args, "--max-downloads") {
+		t.Errorf("expected no --max-downloads in args %q", args)
+	}
+
+	var ids []string
+	for _, e := range result.Info.Entries {
+		ids = append(ids, e.ID)
+	}
+	if expected := []string{"e5", "e3"}; !reflect.DeepEqual(expected, ids) {
+		t.Errorf("expected %v got %v", expected, ids)
+	}
+}
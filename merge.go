@@ -0,0 +1,71 @@
+package goutubedl
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// name of merged output file in download temp dir
+const mergedOutputName = "merged"
+
+// filterRequiresMerge returns true if format filter selects formats to be
+// merged, ex: "bestvideo+bestaudio" or "bv*+ba/b". "+" inside a [...] format
+// filter is not a merge.
+func filterRequiresMerge(filter string) bool {
+	depth := 0
+	for _, c := range filter {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '+':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergedFile returns path to merged output file in tempPath, intermediate
+// format files are named "merged.f<id>.<ext>" if kept
+func mergedFile(tempPath string) (string, error) {
+	entries, err := os.ReadDir(tempPath)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, mergedOutputName+".") || strings.Count(name, ".") != 1 {
+			continue
+		}
+		switch path.Ext(name) {
+		case ".part", ".ytdl", ".temp":
+			continue
+		}
+		return path.Join(tempPath, name), nil
+	}
+	return "", errors.New("merged file not found")
+}
+
+// copyMergedFile copies merged output file to w, a closed reader is not an
+// error same as closing a download before end
+func copyMergedFile(w io.Writer, tempPath string) error {
+	p, err := mergedFile(tempPath)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		return err
+	}
+	return nil
+}
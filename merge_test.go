@@ -0,0 +1,136 @@
+package goutubedl_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl"
+)
+
+// syncBuffer buffer safe to write from youtube-dl output goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDownloadMergeFake(t *testing.T) {
+	useFake(t)
+
+	newResult := func(t *testing.T) (goutubedl.Result, *syncBuffer, string) {
+		t.Helper()
+		// temp dir used for merging
+		tempDir := t.TempDir()
+		t.Setenv("TMPDIR", tempDir)
+		stderr := &syncBuffer{}
+		result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{
+			MergeOutputFormat: "mkv",
+			StderrFn:          func(cmd *exec.Cmd) io.Writer { return stderr },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result, stderr, tempDir
+	}
+	assertEmptyDir := func(t *testing.T, dir string) {
+		t.Helper()
+		if files := dirFiles(t, dir); len(files) > 0 {
+			t.Errorf("expected temp files to be removed got %v", files)
+		}
+	}
+
+	for _, c := range []struct {
+		filter              string
+		expectedDestination string
+	}{
+		{"bestvideo+bestaudio", "/merged.mkv\n"},
+		{"bv*+ba/b", "/merged.mkv\n"},
+		{"best", "Destination: -\n"},
+		{"b[format_note*=a+b]", "Destination: -\n"},
+	} {
+		t.Run(c.filter, func(t *testing.T) {
+			result, stderr, tempDir := newResult(t)
+			dr, err := result.Download(context.Background(), c.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := readFakeDownload(t, dr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.ID != "single" {
+				t.Errorf("expected single got %q", info.ID)
+			}
+			if !strings.Contains(stderr.String(), c.expectedDestination) {
+				t.Errorf("expected %q in output %q", c.expectedDestination, stderr.String())
+			}
+			assertEmptyDir(t, tempDir)
+		})
+	}
+
+	t.Run("failure", func(t *testing.T) {
+		os.Setenv(fakeFailEnv, "single")
+		defer os.Unsetenv(fakeFailEnv)
+		result, _, tempDir := newResult(t)
+		dr, err := result.Download(context.Background(), "bestvideo+bestaudio")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(dr)
+		if err := dr.Close(); err != nil {
+			t.Errorf("expected no error got %v", err)
+		}
+		if len(b) != 0 {
+			t.Errorf("expected no data got %q", b)
+		}
+		assertEmptyDir(t, tempDir)
+	})
+
+	t.Run("close_before_end_slow", func(t *testing.T) {
+		os.Setenv(fakeSlowEnv, "1")
+		defer os.Unsetenv(fakeSlowEnv)
+		result, _, tempDir := newResult(t)
+		dr, err := result.Download(context.Background(), "bestvideo+bestaudio")
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		if err := dr.Close(); err != nil {
+			t.Errorf("expected no error got %v", err)
+		}
+		if d := time.Since(start); d > 10*time.Second {
+			t.Errorf("expected close to stop download got %s", d)
+		}
+		assertEmptyDir(t, tempDir)
+	})
+
+	t.Run("close_before_end", func(t *testing.T) {
+		result, _, tempDir := newResult(t)
+		dr, err := result.Download(context.Background(), "bestvideo+bestaudio")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dr.Close(); err != nil {
+			t.Errorf("expected no error got %v", err)
+		}
+		assertEmptyDir(t, tempDir)
+	})
+}
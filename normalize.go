@@ -0,0 +1,124 @@
+package goutubedl
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Key returns a stable identity for the media, "<lowercase extractor key>:<id>",
+// ex: "youtube:jgVhBThJdXc". Returns empty string if extractor key or id is
+// unknown.
+func (info Info) Key() string {
+	extractorKey := info.extractorKey()
+	if extractorKey == "" || info.ID == "" {
+		return ""
+	}
+	return extractorKey + ":" + info.ID
+}
+
+// ArchiveID returns same identity as Key but in youtube-dl download archive
+// line format, "<lowercase extractor key> <id>", ex: "youtube jgVhBThJdXc".
+func (info Info) ArchiveID() string {
+	extractorKey := info.extractorKey()
+	if extractorKey == "" || info.ID == "" {
+		return ""
+	}
+	return extractorKey + " " + info.ID
+}
+
+func (info Info) extractorKey() string {
+	if info.ExtractorKey != "" {
+		return strings.ToLower(info.ExtractorKey)
+	}
+	return strings.ToLower(info.IEKey)
+}
+
+var youtubeIDRe = regexp.MustCompile(`^[0-9A-Za-z_-]{11}$`)
+
+var youtubeHosts = map[string]bool{
+	"youtube.com":              true,
+	"www.youtube.com":          true,
+	"m.youtube.com":            true,
+	"music.youtube.com":        true,
+	"youtube-nocookie.com":     true,
+	"www.youtube-nocookie.com": true,
+}
+
+// query parameters only used for tracking
+var trackingQueryParams = map[string]bool{
+	"fbclid": true,
+	"gclid":  true,
+}
+
+// youtubeVideoID returns video id for the common YouTube video URL shapes
+func youtubeVideoID(u *url.URL) (string, bool) {
+	host := strings.ToLower(u.Hostname())
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var id string
+	switch {
+	case host == "youtu.be" || host == "www.youtu.be":
+		id = parts[0]
+	case !youtubeHosts[host]:
+		return "", false
+	case len(parts) == 1 && parts[0] == "watch":
+		id = u.Query().Get("v")
+	case len(parts) == 2 && (parts[0] == "embed" ||
+		parts[0] == "v" ||
+		parts[0] == "e" ||
+		parts[0] == "shorts" ||
+		parts[0] == "live"):
+		id = parts[1]
+	}
+
+	return id, youtubeIDRe.MatchString(id)
+}
+
+// NormalizeURL returns a canonical form of URL without spawning youtube-dl.
+// Common YouTube video URL shapes like youtu.be, m.youtube.com, embed,
+// shorts and watch URLs with playlist parameters are normalized to
+// "https://www.youtube.com/watch?v=<id>". Other URLs get lowercase scheme
+// and host, no fragment, no tracking query parameters and sorted query.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+
+	if id, ok := youtubeVideoID(u); ok {
+		return "https://www.youtube.com/watch?v=" + id, nil
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	q := u.Query()
+	for k := range q {
+		if trackingQueryParams[k] || strings.HasPrefix(k, "utm_") {
+			q.Del(k)
+		}
+	}
+	// Encode sorts by key
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// KeyFromURL returns the same key as Info.Key would for URLs with a known
+// shape, currently YouTube video URLs, without spawning youtube-dl.
+func KeyFromURL(rawURL string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", false
+	}
+	if id, ok := youtubeVideoID(u); ok {
+		return "youtube:" + id, true
+	}
+	return "", false
+}
@@ -0,0 +1,66 @@
+package goutubedl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestInfoKey(t *testing.T) {
+	for _, c := range []struct {
+		info     goutubedl.Info
+		expected string
+	}{
+		{goutubedl.Info{ID: "jgVhBThJdXc", ExtractorKey: "Youtube"}, "youtube:jgVhBThJdXc"},
+		{goutubedl.Info{ID: "jgVhBThJdXc", IEKey: "Youtube"}, "youtube:jgVhBThJdXc"},
+		{goutubedl.Info{ID: "jgVhBThJdXc"}, ""},
+		{goutubedl.Info{ExtractorKey: "Youtube"}, ""},
+	} {
+		if k := c.info.Key(); k != c.expected {
+			t.Errorf("expected %q got %q", c.expected, k)
+		}
+		expectedArchiveID := strings.Replace(c.expected, ":", " ", 1)
+		if id := c.info.ArchiveID(); id != expectedArchiveID {
+			t.Errorf("expected archive id %q got %q", expectedArchiveID, id)
+		}
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	const youtubeURL = "https://www.youtube.com/watch?v=jgVhBThJdXc"
+	for _, c := range []struct {
+		url         string
+		expected    string
+		expectedKey string
+	}{
+		{"https://youtu.be/jgVhBThJdXc?si=abc", youtubeURL, "youtube:jgVhBThJdXc"},
+		{"https://m.youtube.com/watch?v=jgVhBThJdXc&feature=share", youtubeURL, "youtube:jgVhBThJdXc"},
+		{"https://www.youtube.com/watch?v=jgVhBThJdXc&list=PLX0g748fkegS54oiDN4AXKl7BR7mLIydP&index=2", youtubeURL, "youtube:jgVhBThJdXc"},
+		{"https://www.youtube.com/embed/jgVhBThJdXc", youtubeURL, "youtube:jgVhBThJdXc"},
+		{"https://www.youtube-nocookie.com/embed/jgVhBThJdXc?start=10", youtubeURL, "youtube:jgVhBThJdXc"},
+		{"https://youtube.com/shorts/jgVhBThJdXc", youtubeURL, "youtube:jgVhBThJdXc"},
+		{"https://music.youtube.com/watch?v=jgVhBThJdXc", youtubeURL, "youtube:jgVhBThJdXc"},
+		{
+			"https://www.youtube.com/playlist?list=PLX0g748fkegS54oiDN4AXKl7BR7mLIydP",
+			"https://www.youtube.com/playlist?list=PLX0g748fkegS54oiDN4AXKl7BR7mLIydP",
+			"",
+		},
+		{"HTTPS://Media.CCC.de:443/v/blinkencount#t=10", "https://media.ccc.de/v/blinkencount", ""},
+		{"https://example.com/v?b=2&utm_source=x&a=1", "https://example.com/v?a=1&b=2", ""},
+	} {
+		t.Run(c.url, func(t *testing.T) {
+			u, err := goutubedl.NormalizeURL(c.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u != c.expected {
+				t.Errorf("expected %q got %q", c.expected, u)
+			}
+			k, ok := goutubedl.KeyFromURL(c.url)
+			if k != c.expectedKey || ok != (c.expectedKey != "") {
+				t.Errorf("expected key %q got %q %v", c.expectedKey, k, ok)
+			}
+		})
+	}
+}
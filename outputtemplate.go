@@ -0,0 +1,512 @@
+package goutubedl
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// OutputTemplateNA is used for missing fields without a default, same as
+// youtube-dl's default --output-na-placeholder
+const OutputTemplateNA = "NA"
+
+// Filename expands youtube-dl output template tmpl, ex:
+// "%(title)s [%(id)s].%(ext)s", using fields of info overridden by format
+// fields if format is not nil. Field values are sanitized with
+// SanitizeFilename, same as youtube-dl does with --restrict-filenames.
+// See ExpandTemplate for supported syntax.
+func (info Info) Filename(tmpl string, format *Format) (string, error) {
+	return info.expandTemplate(tmpl, format, true)
+}
+
+// ExpandTemplate expands youtube-dl output template tmpl without sanitizing
+// field values, same as --print. Supports fields with traversal
+// "%(formats.-1.format_id)s", maths "%(playlist_index+1)d", date formatting
+// "%(upload_date>%Y-%m-%d)s", alternatives "%(track,title)s", replacement
+// "%(chapter&Chapter {})s", defaults "%(uploader|Unknown)s" and the
+// conversion types "diouxXeEfFgGcsjlqBS" with flags, width and precision.
+// Zero values in info are treated as missing as they can't be told apart.
+func (info Info) ExpandTemplate(tmpl string, format *Format) (string, error) {
+	return info.expandTemplate(tmpl, format, false)
+}
+
+func (info Info) expandTemplate(tmpl string, format *Format, sanitize bool) (string, error) {
+	fields, err := templateFields(info, format)
+	if err != nil {
+		return "", err
+	}
+
+	sb := &strings.Builder{}
+	for i := 0; i < len(tmpl); {
+		c := tmpl[i]
+		if c != '%' {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		switch {
+		case strings.HasPrefix(tmpl[i:], "%%"):
+			sb.WriteByte('%')
+			i += 2
+			continue
+		case !strings.HasPrefix(tmpl[i:], "%("):
+			// lone % is literal
+			sb.WriteByte('%')
+			i++
+			continue
+		}
+
+		keyEnd := strings.IndexByte(tmpl[i:], ')')
+		if keyEnd == -1 {
+			return "", fmt.Errorf("output template: unclosed field at %d", i)
+		}
+		key := tmpl[i+2 : i+keyEnd]
+		m := templateFormatRe.FindString(tmpl[i+keyEnd+1:])
+		if m == "" {
+			return "", fmt.Errorf("output template: missing conversion type for field %q", key)
+		}
+		s, err := expandTemplateField(key, m, fields, sanitize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(s)
+		i += keyEnd + 1 + len(m)
+	}
+
+	return sb.String(), nil
+}
+
+// flags, width, precision, length modifier and type
+var templateFormatRe = regexp.MustCompile(`^[#0\-+ ]*\d*(?:\.\d+)?[hlL]?[diouxXeEfFgGcrsajlqBUDS]`)
+
+// templateFields returns info as JSON fields with format fields on top
+func templateFields(info Info, format *Format) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	for _, v := range []interface{}{info, format} {
+		if f, ok := v.(*Format); ok && f == nil {
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+		for k, v := range m {
+			if !templateMissing(v) {
+				fields[k] = v
+			}
+		}
+	}
+	return fields, nil
+}
+
+func templateMissing(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// splitUnescaped splits s at first sep not escaped by backslash
+func splitUnescaped(s string, sep byte) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+func splitAllUnescaped(s string, sep byte) []string {
+	var parts []string
+	for {
+		part, rest, ok := splitUnescaped(s, sep)
+		parts = append(parts, part)
+		if !ok {
+			return parts
+		}
+		s = rest
+	}
+}
+
+var templateUnescaper = strings.NewReplacer(`\,`, ",", `\|`, "|", `\&`, "&", `\\`, `\`)
+
+func expandTemplateField(key string, format string, fields map[string]interface{}, sanitize bool) (string, error) {
+	key, defaultValue, hasDefault := splitUnescaped(key, '|')
+	key, replacement, hasReplacement := splitUnescaped(key, '&')
+	if !hasDefault {
+		defaultValue = OutputTemplateNA
+	}
+
+	var value interface{}
+	for _, expr := range splitAllUnescaped(key, ',') {
+		v, err := evalTemplateExpr(expr, fields)
+		if err != nil {
+			return "", err
+		}
+		if v != nil {
+			value = v
+			break
+		}
+	}
+
+	if value != nil && hasReplacement {
+		value = strings.Replace(templateUnescaper.Replace(replacement), "{}", templateString(value), -1)
+	}
+
+	verb := format[len(format)-1]
+	spec := strings.TrimRight(format[:len(format)-1], "hlL")
+	// non-number for number conversion is same as missing
+	if _, ok := templateNumber(value); value == nil ||
+		(strings.IndexByte("diuoxXceEfFgG", verb) != -1 && !ok) {
+		value = templateUnescaper.Replace(defaultValue)
+		verb = 's'
+	}
+
+	var s string
+	switch verb {
+	case 'd', 'i', 'u', 'o', 'x', 'X', 'c', 'e', 'E', 'f', 'F', 'g', 'G':
+		n, _ := templateNumber(value)
+		switch verb {
+		case 'd', 'i', 'u':
+			s = fmt.Sprintf("%"+spec+"d", int64(n))
+		case 'o', 'x', 'X':
+			s = fmt.Sprintf("%"+spec+string(verb), int64(n))
+		case 'c':
+			s = fmt.Sprintf("%"+spec+"c", rune(n))
+		default:
+			s = fmt.Sprintf("%"+spec+string(verb), n)
+		}
+		return s, nil
+	case 'j':
+		var b []byte
+		var err error
+		if strings.Contains(spec, "#") {
+			b, err = json.MarshalIndent(value, "", "    ")
+		} else {
+			b, err = json.Marshal(value)
+		}
+		if err != nil {
+			return "", err
+		}
+		s = string(b)
+		spec = strings.Replace(spec, "#", "", -1)
+	case 'l':
+		sep := ", "
+		if strings.Contains(spec, "#") {
+			sep = "\n"
+			spec = strings.Replace(spec, "#", "", -1)
+		}
+		var ss []string
+		if vs, ok := value.([]interface{}); ok {
+			for _, v := range vs {
+				ss = append(ss, templateString(v))
+			}
+		} else {
+			ss = []string{templateString(value)}
+		}
+		s = strings.Join(ss, sep)
+	case 'q':
+		s = shellQuote(templateString(value))
+	case 'S':
+		s = SanitizeFilename(templateString(value))
+	default:
+		s = templateString(value)
+	}
+
+	if sanitize && verb != 'S' {
+		s = SanitizeFilename(s)
+	}
+
+	return fmt.Sprintf("%"+spec+"s", s), nil
+}
+
+// evalTemplateExpr evaluates "field.traversal+maths>strftime", returns nil
+// value if field is missing
+func evalTemplateExpr(expr string, fields map[string]interface{}) (interface{}, error) {
+	expr, strftimeFormat, hasStrftime := splitUnescaped(expr, '>')
+
+	m := templateExprRe.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, fmt.Errorf("output template: invalid field %q", expr)
+	}
+	field, maths := m[1], m[2]
+
+	value := traverseTemplateField(fields, field)
+	for _, mm := range templateMathsRe.FindAllStringSubmatch(maths, -1) {
+		if value == nil {
+			break
+		}
+		n, ok := templateNumber(value)
+		if !ok {
+			return nil, fmt.Errorf("output template: maths on non-number field %q", field)
+		}
+		operand, err := strconv.ParseFloat(mm[2], 64)
+		if err != nil {
+			v := traverseTemplateField(fields, mm[2])
+			if v == nil {
+				value = nil
+				break
+			}
+			if operand, ok = templateNumber(v); !ok {
+				return nil, fmt.Errorf("output template: maths on non-number field %q", mm[2])
+			}
+		}
+		if mm[1] == "-" {
+			operand = -operand
+		}
+		value = n + operand
+	}
+
+	if value != nil && hasStrftime {
+		t, ok := templateTime(value)
+		if !ok {
+			return nil, nil
+		}
+		value = strftime(t, templateUnescaper.Replace(strftimeFormat))
+	}
+
+	return value, nil
+}
+
+var templateExprRe = regexp.MustCompile(`^(\w+(?:\.(?:-?\d*:-?\d*(?::-?\d*)?|-?\d+|\w+))*)((?:[-+]-?(?:\d+(?:\.\d+)?|\w+(?:\.\w+)*))*)$`)
+var templateMathsRe = regexp.MustCompile(`([-+])(-?(?:\d+(?:\.\d+)?|\w+(?:\.\w+)*))`)
+
+// traverseTemplateField returns value at dot separated path, negative list
+// indexes are from the end, "start:end:step" slices lists and a field name
+// after a list gets the field of each element
+func traverseTemplateField(fields map[string]interface{}, path string) interface{} {
+	var v interface{} = fields
+	for _, part := range strings.Split(path, ".") {
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			v = vv[part]
+		case []interface{}:
+			if strings.Contains(part, ":") {
+				v = sliceTemplateList(vv, part)
+				continue
+			}
+			i, err := strconv.Atoi(part)
+			if err != nil {
+				// field of each element, ex: "formats.:.format_id"
+				var r []interface{}
+				for _, e := range vv {
+					if m, ok := e.(map[string]interface{}); ok && !templateMissing(m[part]) {
+						r = append(r, m[part])
+					}
+				}
+				v = r
+				continue
+			}
+			if i < 0 {
+				i += len(vv)
+			}
+			if i < 0 || i >= len(vv) {
+				return nil
+			}
+			v = vv[i]
+		default:
+			return nil
+		}
+	}
+	if templateMissing(v) {
+		return nil
+	}
+	return v
+}
+
+func sliceTemplateList(l []interface{}, s string) []interface{} {
+	parts := strings.Split(s, ":")
+	idx := func(s string, def int) int {
+		if s == "" {
+			return def
+		}
+		i, _ := strconv.Atoi(s)
+		if i < 0 {
+			i += len(l)
+		}
+		if i < 0 {
+			i = 0
+		}
+		if i > len(l) {
+			i = len(l)
+		}
+		return i
+	}
+	start, end, step := idx(parts[0], 0), idx(parts[1], len(l)), 1
+	if len(parts) > 2 && parts[2] != "" {
+		step, _ = strconv.Atoi(parts[2])
+		if step < 1 {
+			step = 1
+		}
+	}
+	var r []interface{}
+	for i := start; i < end; i += step {
+		r = append(r, l[i])
+	}
+	return r
+}
+
+func templateNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// templateString formats value same as python str()
+func templateString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// templateTime returns time for a YYYYMMDD date or UNIX timestamp
+func templateTime(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case string:
+		t, err := time.Parse("20060102", v)
+		return t, err == nil
+	case float64:
+		sec, frac := math.Modf(v)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+var strftimeLayouts = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'H': "15", 'I': "03",
+	'M': "04", 'S': "05", 'p': "PM", 'b': "Jan", 'B': "January",
+	'a': "Mon", 'A': "Monday", 'Z': "MST", 'z': "-0700",
+}
+
+func strftime(t time.Time, format string) string {
+	sb := &strings.Builder{}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			sb.WriteByte(format[i])
+			continue
+		}
+		i++
+		d := format[i]
+		switch {
+		case d == '%':
+			sb.WriteByte('%')
+		case d == 'j':
+			fmt.Fprintf(sb, "%03d", t.YearDay())
+		case d == 'f':
+			fmt.Fprintf(sb, "%06d", t.Nanosecond()/1000)
+		case strftimeLayouts[d] != "":
+			sb.WriteString(t.Format(strftimeLayouts[d]))
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(d)
+		}
+	}
+	return sb.String()
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("@%+=:,./-_", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'"'"'`, -1) + "'"
+}
+
+var accentChars = map[rune]string{}
+
+func init() {
+	from := []rune("ÂÃÄÀÁÅÆÇÈÉÊËÌÍÎÏÐÑÒÓÔÕÖŐØŒÙÚÛÜŰÝÞßàáâãäåæçèéêëìíîïðñòóôõöőøœùúûüűýþÿ")
+	to := []string{
+		"A", "A", "A", "A", "A", "A", "AE", "C", "E", "E", "E", "E", "I", "I", "I", "I", "D", "N",
+		"O", "O", "O", "O", "O", "O", "O", "OE", "U", "U", "U", "U", "U", "Y", "TH", "ss",
+		"a", "a", "a", "a", "a", "a", "ae", "c", "e", "e", "e", "e", "i", "i", "i", "i", "o", "n",
+		"o", "o", "o", "o", "o", "o", "o", "oe", "u", "u", "u", "u", "u", "y", "th", "y",
+	}
+	for i, r := range from {
+		accentChars[r] = to[i]
+	}
+}
+
+var timestampColonRe = regexp.MustCompile(`[0-9]+(?::[0-9]+)+`)
+
+// SanitizeFilename sanitizes s to be used as part of a filename same as
+// youtube-dl does with --restrict-filenames, ex: "a/b: c?" is "a_b_-_c".
+// Accented characters are replaced by ASCII but unlike youtube-dl no unicode
+// normalization is done.
+func SanitizeFilename(s string) string {
+	s = timestampColonRe.ReplaceAllStringFunc(s, func(t string) string {
+		return strings.Replace(t, ":", "_", -1)
+	})
+
+	sb := &strings.Builder{}
+	for _, r := range s {
+		if a, ok := accentChars[r]; ok {
+			sb.WriteString(a)
+			continue
+		}
+		switch {
+		case r == '?' || r < 32 || r == 127 || r == '"':
+		case r == ':':
+			sb.WriteString("_-")
+		case strings.ContainsRune(`\/|*<>`, r):
+			sb.WriteByte('_')
+		case strings.ContainsRune("!&'()[]{}$;`^,#", r) || unicode.IsSpace(r) || r > 127:
+			if !unicode.In(r, unicode.C, unicode.M) {
+				sb.WriteByte('_')
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
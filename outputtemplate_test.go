@@ -0,0 +1,126 @@
+package goutubedl_test
+
+import (
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	info := goutubedl.Info{
+		ID:            "abc-123",
+		Title:         "Hello: World/Test? Ärlig",
+		UploadDate:    "20240102",
+		Timestamp:     1700000000,
+		Duration:      61.5,
+		PlaylistIndex: 3,
+		Formats: []goutubedl.Format{
+			{FormatID: "18", Ext: "mp4", Height: 360},
+			{FormatID: "22", Ext: "mp4", Height: 720},
+		},
+		Format: goutubedl.Format{FormatID: "22", Ext: "mp4", Height: 720},
+	}
+	format := &goutubedl.Format{FormatID: "251", Ext: "webm", ACodec: "opus"}
+
+	for _, c := range []struct {
+		tmpl     string
+		format   *goutubedl.Format
+		expected string
+	}{
+		{"%(title)s [%(id)s].%(ext)s", nil, "Hello: World/Test? Ärlig [abc-123].mp4"},
+		{"%(id)s.%(ext)s", format, "abc-123.webm"},
+		{"%(format_id)s %(acodec)s", format, "251 opus"},
+		{"%(uploader)s", nil, "NA"},
+		{"%(uploader|Unknown)s", nil, "Unknown"},
+		{"%(uploader,title)s", nil, "Hello: World/Test? Ärlig"},
+		{"%(uploader,channel|none\\, really)s", nil, "none, really"},
+		{"%(upload_date>%Y-%m-%d)s", nil, "2024-01-02"},
+		{"%(timestamp>%Y-%m-%d %H:%M:%S)s", nil, "2023-11-14 22:13:20"},
+		{"%(release_date>%Y,upload_date>%Y)s", nil, "2024"},
+		{"%(playlist_index)03d", nil, "003"},
+		{"%(playlist_index+10)d", nil, "13"},
+		{"%(playlist_index-1)d", nil, "2"},
+		{"%(duration)s %(duration).2f %(duration)d", nil, "61.5 61.50 61"},
+		{"%(height)5d|%(height)-5d|", nil, "  720|720  |"},
+		{"%(height)x", nil, "2d0"},
+		{"%(title)d", nil, "NA"},
+		{"%(formats.0.format_id)s %(formats.-1.height)d", nil, "18 720"},
+		{"%(formats.:.format_id)l", nil, "18, 22"},
+		{"%(formats.5.format_id|missing)s", nil, "missing"},
+		{"%(playlist_index&#{}|single)s", nil, "#3"},
+		{"%(uploader&has uploader|no uploader)s", nil, "no uploader"},
+		{"%(id)q %(title)q", nil, "abc-123 'Hello: World/Test? Ärlig'"},
+		{"%(formats.:.height)j", nil, "[360,720]"},
+		{"%(title).5s", nil, "Hello"},
+		{"%(title)S", nil, "Hello_-_World_Test_Arlig"},
+		{"100% %%(id)s", nil, "100% %(id)s"},
+	} {
+		t.Run(c.tmpl, func(t *testing.T) {
+			actual, err := info.ExpandTemplate(c.tmpl, c.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %q got %q", c.expected, actual)
+			}
+		})
+	}
+
+	for _, tmpl := range []string{"%(title", "%(title)", "%(title)y", "%(a b)s"} {
+		if _, err := info.ExpandTemplate(tmpl, nil); err == nil {
+			t.Errorf("%s: expected error", tmpl)
+		}
+	}
+}
+
+func TestFilename(t *testing.T) {
+	info := goutubedl.Info{
+		ID:         "abc-123",
+		Title:      "Hello: World/Test? Ärlig 12:34",
+		UploadDate: "20240102",
+		Format:     goutubedl.Format{Ext: "mp4"},
+	}
+	for _, c := range []struct {
+		tmpl     string
+		expected string
+	}{
+		{"%(title)s [%(id)s].%(ext)s", "Hello_-_World_Test_Arlig_12_34 [abc-123].mp4"},
+		{"%(upload_date>%Y/%m)s/%(id)s.%(ext)s", "2024_01/abc-123.mp4"},
+		{"%(uploader|a/b)s", "a_b"},
+	} {
+		t.Run(c.tmpl, func(t *testing.T) {
+			actual, err := info.Filename(c.tmpl, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %q got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	for _, c := range []struct {
+		s        string
+		expected string
+	}{
+		{"abc", "abc"},
+		{"a b", "a_b"},
+		{"a/b\\c|d*e<f>g", "a_b_c_d_e_f_g"},
+		{"what?", "what"},
+		{`say "hi"`, "say_hi"},
+		{"a: b", "a_-_b"},
+		{"10:20:30", "10_20_30"},
+		{"(a)[b]{c}!&$;`^,#'", "_a__b__c__________"},
+		{"Ærø ß", "AEro_ss"},
+		{"日本", "__"},
+		{"?", "_"},
+	} {
+		t.Run(c.s, func(t *testing.T) {
+			if actual := goutubedl.SanitizeFilename(c.s); actual != c.expected {
+				t.Errorf("expected %q got %q", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package goutubedl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// Pager fetches entries of a playlist or channel one page at a time using
+// PlaylistStart and PlaylistEnd. Use with Options.FlatPlaylist to make
+// fetching a page fast.
+type Pager struct {
+	rawURL   string
+	options  Options
+	pageSize uint
+	start    uint
+	total    int
+	count    int
+	done     bool
+	seen     map[string]bool
+}
+
+// NewPager returns a Pager for rawURL fetching pageSize entries per page.
+// Type defaults to TypePlaylist and PlaylistStart and PlaylistEnd in options
+// are ignored.
+func NewPager(rawURL string, options Options, pageSize uint) *Pager {
+	if options.Type == TypeAny {
+		options.Type = TypePlaylist
+	}
+	if pageSize == 0 {
+		pageSize = 50
+	}
+	options.PlaylistStart = 0
+	options.PlaylistEnd = 0
+
+	return &Pager{
+		rawURL:   rawURL,
+		options:  options,
+		pageSize: pageSize,
+		start:    1,
+		total:    -1,
+		seen:     map[string]bool{},
+	}
+}
+
+// Next fetches next page. Result Info.Entries are the entries of the page,
+// entries already returned in a previous page are removed as entries might
+// move between pages. Returns io.EOF when there are no more entries.
+// For channels with multiple tabs a page has up to page size entries per tab.
+func (p *Pager) Next(ctx context.Context) (Result, error) {
+	for !p.done {
+		options := p.options
+		options.PlaylistStart = p.start
+		options.PlaylistEnd = p.start + p.pageSize - 1
+		r, err := New(ctx, p.rawURL, options)
+		if err != nil {
+			return Result{}, err
+		}
+
+		nested := false
+		var entries []Info
+		for _, e := range r.Info.Entries {
+			nested = nested || e.ChannelTab != ""
+			key := string(e.ChannelTab) + " " + e.ID
+			if p.seen[key] {
+				continue
+			}
+			p.seen[key] = true
+			entries = append(entries, e)
+		}
+		p.count += len(entries)
+		// playlist count for a channel with tabs is not the number of entries
+		if !nested && r.Info.PlaylistCount > 0 {
+			p.total = int(r.Info.PlaylistCount)
+		}
+
+		// entries might have been filtered or be unavailable so use number of
+		// entries youtube-dl returned to know if this was the last page
+		rawCount, err := rawPageEntryCount(r.RawJSON)
+		if err != nil {
+			return Result{}, err
+		}
+		lastPage := uint(rawCount) < p.pageSize ||
+			(!nested && r.Info.PlaylistCount > 0 && float64(p.start+p.pageSize-1) >= r.Info.PlaylistCount)
+		p.start += p.pageSize
+		if lastPage {
+			p.done = true
+			p.total = p.count
+		}
+		// whole page might have been already seen entries
+		if len(entries) > 0 {
+			r.Info.Entries = entries
+			return r, nil
+		}
+	}
+
+	return Result{}, io.EOF
+}
+
+// Total returns total number of entries if known, otherwise -1. Known after
+// first page if youtube-dl knows the playlist count, otherwise after last page.
+func (p *Pager) Total() int {
+	return p.total
+}
+
+// rawPageEntryCount returns number of entries including null entries in
+// rawJSON, for nested playlists like channel tabs the max number of entries
+// in a nested playlist
+func rawPageEntryCount(rawJSON []byte) (int, error) {
+	var page struct {
+		Entries []json.RawMessage `json:"entries"`
+	}
+	if err := json.Unmarshal(rawJSON, &page); err != nil {
+		return 0, err
+	}
+
+	count := len(page.Entries)
+	nestedCount := -1
+	for _, raw := range page.Entries {
+		var nested struct {
+			Type    string            `json:"_type"`
+			Entries []json.RawMessage `json:"entries"`
+		}
+		if err := json.Unmarshal(raw, &nested); err != nil {
+			return 0, err
+		}
+		if nested.Type == "playlist" && len(nested.Entries) > nestedCount {
+			nestedCount = len(nested.Entries)
+		}
+	}
+	if nestedCount != -1 {
+		count = nestedCount
+	}
+
+	return count, nil
+}
@@ -0,0 +1,76 @@
+package goutubedl_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestPagerFake(t *testing.T) {
+	useFake(t)
+
+	for _, c := range []struct {
+		url                string
+		options            goutubedl.Options
+		expectedPages      [][]string
+		expectedFirstTotal int
+		expectedTotal      int
+	}{
+		{
+			fakePlaylistURL,
+			goutubedl.Options{FlatPlaylist: true},
+			[][]string{{"e5", "e4"}, {"e3", "e2"}, {"e1"}},
+			5,
+			5,
+		},
+		{
+			fakeChannelURL,
+			goutubedl.Options{Type: goutubedl.TypeChannel, FlatPlaylist: true},
+			[][]string{{"v3", "v2", "s2", "s1"}, {"v1"}},
+			-1,
+			5,
+		},
+		{
+			// filtered entries should not end paging early
+			fakePlaylistURL,
+			goutubedl.Options{FlatPlaylist: true, MatchFilter: goutubedl.MatchFilter{
+				Func: func(info goutubedl.Info) bool { return info.ID != "e4" && info.ID != "e3" },
+			}},
+			[][]string{{"e5"}, {"e2"}, {"e1"}},
+			5,
+			3,
+		},
+	} {
+		t.Run(c.url, func(t *testing.T) {
+			p := goutubedl.NewPager(c.url, c.options, 2)
+
+			var pages [][]string
+			for {
+				r, err := p.Next(context.Background())
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				if len(pages) == 0 && p.Total() != c.expectedFirstTotal {
+					t.Errorf("expected total %d after first page got %d", c.expectedFirstTotal, p.Total())
+				}
+				var ids []string
+				for _, e := range r.Info.Entries {
+					ids = append(ids, e.ID)
+				}
+				pages = append(pages, ids)
+			}
+
+			if !reflect.DeepEqual(c.expectedPages, pages) {
+				t.Errorf("expected pages %v got %v", c.expectedPages, pages)
+			}
+			if p.Total() != c.expectedTotal {
+				t.Errorf("expected total %d got %d", c.expectedTotal, p.Total())
+			}
+		})
+	}
+}
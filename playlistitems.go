@@ -0,0 +1,293 @@
+package goutubedl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// PlaylistRange range of playlist entries, same semantics as one comma
+// separated item of --playlist-items.
+// Indexes start at 1 and negative indexes count from the end.
+type PlaylistRange struct {
+	Start int // first entry, zero is from first (or last if Step is negative)
+	End   int // last entry inclusive, zero is to last (or first if Step is negative)
+	Step  int // zero is 1
+	// single entry at Start, needed as zero Start and End otherwise means all entries
+	single bool
+}
+
+// PlaylistItem range with the single entry at index
+func PlaylistItem(index int) PlaylistRange {
+	return PlaylistRange{Start: index, End: index, single: true}
+}
+
+func (r PlaylistRange) String() string {
+	if r.single {
+		return strconv.Itoa(r.Start)
+	}
+
+	var sb strings.Builder
+	if r.Start != 0 {
+		sb.WriteString(strconv.Itoa(r.Start))
+	}
+	sb.WriteString(":")
+	if r.End != 0 {
+		sb.WriteString(strconv.Itoa(r.End))
+	}
+	if r.Step != 0 {
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(r.Step))
+	}
+
+	return sb.String()
+}
+
+// indexes returns 1-based indexes selected by range for a playlist with n entries
+func (r PlaylistRange) indexes(n int) []int {
+	resolve := func(i int) int {
+		if i < 0 {
+			return n + i + 1
+		}
+		return i
+	}
+
+	if r.single {
+		i := resolve(r.Start)
+		if i < 1 || i > n {
+			return nil
+		}
+		return []int{i}
+	}
+
+	step := r.Step
+	if step == 0 {
+		step = 1
+	}
+	start, end := resolve(r.Start), resolve(r.End)
+	if r.Start == 0 {
+		start = 1
+		if step < 0 {
+			start = n
+		}
+	}
+	if r.End == 0 {
+		end = n
+		if step < 0 {
+			end = 1
+		}
+	}
+
+	var is []int
+	for i := start; (step > 0 && i <= end) || (step < 0 && i >= end); i += step {
+		if i >= 1 && i <= n {
+			is = append(is, i)
+		}
+	}
+
+	return is
+}
+
+// PlaylistItems selects playlist entries, see --playlist-items
+type PlaylistItems []PlaylistRange
+
+// String returns items in --playlist-items syntax
+func (p PlaylistItems) String() string {
+	ss := make([]string, len(p))
+	for i, r := range p {
+		ss[i] = r.String()
+	}
+	return strings.Join(ss, ",")
+}
+
+// Indexes returns selected 1-based indexes for a playlist with n entries.
+// Indexes are in order of selection and duplicates are removed.
+func (p PlaylistItems) Indexes(n int) []int {
+	seen := map[int]bool{}
+	var is []int
+	for _, r := range p {
+		for _, i := range r.indexes(n) {
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			is = append(is, i)
+		}
+	}
+	return is
+}
+
+// ParsePlaylistItems parses --playlist-items syntax, ex: "1,3,5-7,-2:,::2"
+func ParsePlaylistItems(s string) (PlaylistItems, error) {
+	var p PlaylistItems
+	for _, item := range strings.Split(s, ",") {
+		if item == "" {
+			return nil, fmt.Errorf("playlist items %q: empty item", s)
+		}
+
+		sep := strings.Index(item, ":")
+		if sep == -1 {
+			// START-END, skip first character to allow negative START
+			if i := strings.Index(item[1:], "-"); i != -1 {
+				sep = i + 1
+			}
+		}
+		if sep == -1 {
+			i, err := strconv.Atoi(item)
+			if err != nil {
+				return nil, fmt.Errorf("playlist items %q: invalid index %q", s, item)
+			}
+			p = append(p, PlaylistItem(i))
+			continue
+		}
+
+		parts := append([]string{item[0:sep]}, strings.SplitN(item[sep+1:], ":", 2)...)
+		var ns [3]int
+		for i, part := range parts {
+			if part == "" || (i == 1 && (part == "inf" || part == "infinite")) {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("playlist items %q: invalid range %q", s, item)
+			}
+			ns[i] = n
+		}
+		if len(parts) == 3 && ns[2] == 0 {
+			return nil, fmt.Errorf("playlist items %q: step can't be zero", s)
+		}
+
+		p = append(p, PlaylistRange{Start: ns[0], End: ns[1], Step: ns[2]})
+	}
+
+	return p, nil
+}
+
+// entryIndex returns playlist index of entry at position i in Info.Entries.
+// Entries without a playlist_index use their 1-based position.
+func entryIndex(e Info, i int) int {
+	if e.PlaylistIndex > 0 {
+		return int(e.PlaylistIndex)
+	}
+	return i + 1
+}
+
+// selectedEntries returns playlist entries selected by options. Indexes are
+// matched against playlist_index, same as --playlist-items, so for a flattened
+// channel one index can select one entry per tab.
+func (result Result) selectedEntries(options DownloadOptions) []Info {
+	items := options.PlaylistItems
+	if len(items) == 0 {
+		items = PlaylistItems{{}}
+	}
+
+	entries := result.Info.Entries
+	byIndex := map[int][]Info{}
+	n := int(result.Info.PlaylistCount)
+	for i, e := range entries {
+		index := entryIndex(e, i)
+		byIndex[index] = append(byIndex[index], e)
+		if index > n {
+			n = index
+		}
+	}
+
+	var selected []Info
+	for _, i := range items.Indexes(n) {
+		selected = append(selected, byIndex[i]...)
+	}
+
+	if options.PlaylistReverse {
+		for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+			selected[i], selected[j] = selected[j], selected[i]
+		}
+	}
+	if options.PlaylistRandom {
+		rand.Shuffle(len(selected), func(i, j int) {
+			selected[i], selected[j] = selected[j], selected[i]
+		})
+	}
+
+	return selected
+}
+
+// PlaylistDownload downloads selected playlist entries one at a time
+type PlaylistDownload struct {
+	result     Result
+	options    DownloadOptions
+	entries    []Info
+	rawEntries map[string]json.RawMessage
+}
+
+// DownloadPlaylist returns a PlaylistDownload for entries selected by
+// options.PlaylistItems, PlaylistReverse and PlaylistRandom.
+// PlaylistItems indexes are playlist indexes, see DownloadOptions. If not set all entries are selected.
+// Entries are downloaded using the already fetched entry info, see DownloadEntry.
+func (result Result) DownloadPlaylist(options DownloadOptions) (*PlaylistDownload, error) {
+	if result.Options.noInfoDownload {
+		return nil, errors.New("can't download playlist entries without info")
+	}
+	if options.PlaylistIndex != 0 {
+		return nil, errors.New("playlist index option can't be used with playlist download")
+	}
+
+	entries, err := result.rawEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	pd := &PlaylistDownload{
+		result:     result,
+		options:    options,
+		entries:    result.selectedEntries(options),
+		rawEntries: entries,
+	}
+	pd.options.PlaylistItems = nil
+	pd.options.PlaylistReverse = false
+	pd.options.PlaylistRandom = false
+
+	return pd, nil
+}
+
+// Len returns number of entries left to download, including entries that
+// might be skipped
+func (pd *PlaylistDownload) Len() int {
+	return len(pd.entries)
+}
+
+// Next starts download of next selected entry. Returns io.EOF when there are
+// no more entries. On error iteration can continue with next entry.
+// Entries in options.Archive are skipped.
+// Returned DownloadResult should be closed before calling Next again.
+func (pd *PlaylistDownload) Next(ctx context.Context) (Info, *DownloadResult, error) {
+	for len(pd.entries) > 0 {
+		entry := pd.entries[0]
+		pd.entries = pd.entries[1:]
+
+		archived, err := pd.options.archived(entry)
+		if err != nil {
+			return entry, nil, err
+		}
+		if archived {
+			if pd.options.BreakOnExisting {
+				pd.entries = nil
+				break
+			}
+			continue
+		}
+
+		dr, err := pd.result.downloadEntry(ctx, entry, pd.rawEntries, pd.options)
+		if dr != nil {
+			dr.onComplete = func() error { return pd.options.archiveAdd(entry) }
+		}
+
+		return entry, dr, err
+	}
+
+	return Info{}, nil, io.EOF
+}
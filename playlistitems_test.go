@@ -0,0 +1,172 @@
+package goutubedl_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestPlaylistItems(t *testing.T) {
+	for _, c := range []struct {
+		s               string
+		expectedString  string
+		n               int
+		expectedIndexes []int
+	}{
+		{"1", "1", 5, []int{1}},
+		{"1,3,5", "1,3,5", 5, []int{1, 3, 5}},
+		{"2-4", "2:4", 5, []int{2, 3, 4}},
+		{"2:4", "2:4", 5, []int{2, 3, 4}},
+		{"-1", "-1", 5, []int{5}},
+		{"-2:", "-2:", 5, []int{4, 5}},
+		{"-3--2", "-3:-2", 5, []int{3, 4}},
+		{"::2", "::2", 5, []int{1, 3, 5}},
+		{"::-1", "::-1", 5, []int{5, 4, 3, 2, 1}},
+		{"4:2:-1", "4:2:-1", 5, []int{4, 3, 2}},
+		{"3:inf", "3:", 5, []int{3, 4, 5}},
+		{"1,1-2,2", "1,1:2,2", 5, []int{1, 2}},
+		{"7,2-9", "7,2:9", 5, []int{2, 3, 4, 5}},
+	} {
+		t.Run(c.s, func(t *testing.T) {
+			p, err := goutubedl.ParsePlaylistItems(c.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := p.String(); s != c.expectedString {
+				t.Errorf("expected string %q got %q", c.expectedString, s)
+			}
+			if is := p.Indexes(c.n); !reflect.DeepEqual(is, c.expectedIndexes) {
+				t.Errorf("expected indexes %v got %v", c.expectedIndexes, is)
+			}
+		})
+	}
+}
+
+func TestPlaylistItemsInvalid(t *testing.T) {
+	for _, s := range []string{"", "1,,2", "a", "1-b", "1:2:0"} {
+		t.Run(s, func(t *testing.T) {
+			if _, err := goutubedl.ParsePlaylistItems(s); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestDownloadPlaylistFake(t *testing.T) {
+	useFake(t)
+
+	// entries have playlist_index 2 to 5 and ids e4 to e1
+	result, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Type:          goutubedl.TypePlaylist,
+		PlaylistStart: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		items       string
+		reverse     bool
+		expectedIDs []string
+	}{
+		{"2", false, []string{"e4"}},
+		{"1,5", false, []string{"e1"}},
+		{"-2:", false, []string{"e2", "e1"}},
+		{"2-4", true, []string{"e2", "e3", "e4"}},
+	} {
+		t.Run(c.items, func(t *testing.T) {
+			items, err := goutubedl.ParsePlaylistItems(c.items)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pd, err := result.DownloadPlaylist(goutubedl.DownloadOptions{
+				PlaylistItems:   items,
+				PlaylistReverse: c.reverse,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for {
+				entry, dr, err := pd.Next(context.Background())
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				downloaded, err := readFakeDownload(t, dr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if downloaded.ID != entry.ID {
+					t.Errorf("expected download of %s got %s", entry.ID, downloaded.ID)
+				}
+				ids = append(ids, entry.ID)
+			}
+			if !reflect.DeepEqual(c.expectedIDs, ids) {
+				t.Errorf("expected %v got %v", c.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestDownloadWithOptionsPlaylistFake(t *testing.T) {
+	useFake(t)
+
+	// entries have playlist_index 2 to 5 and ids e4 to e1
+	playlist, err := goutubedl.New(context.Background(), fakePlaylistURL, goutubedl.Options{
+		Type:          goutubedl.TypePlaylist,
+		PlaylistStart: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tabs "videos" and "shorts" both have entries with playlist_index 1
+	channel, err := goutubedl.New(context.Background(), fakeChannelURL, goutubedl.Options{
+		Type: goutubedl.TypeChannel,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name       string
+		result     goutubedl.Result
+		options    goutubedl.DownloadOptions
+		expectedID string
+	}{
+		{"index", playlist, goutubedl.DownloadOptions{PlaylistIndex: 2}, "e4"},
+		{"items", playlist, goutubedl.DownloadOptions{PlaylistItems: goutubedl.PlaylistItems{goutubedl.PlaylistItem(-1)}}, "e1"},
+		{"items_range", playlist, goutubedl.DownloadOptions{PlaylistItems: goutubedl.PlaylistItems{{Start: 3, End: 3}}}, "e3"},
+		{"items_none", playlist, goutubedl.DownloadOptions{PlaylistItems: goutubedl.PlaylistItems{goutubedl.PlaylistItem(1)}}, ""},
+		{"items_multiple", playlist, goutubedl.DownloadOptions{PlaylistItems: goutubedl.PlaylistItems{{Start: 2, End: 3}}}, ""},
+		{"channel_ambiguous", channel, goutubedl.DownloadOptions{PlaylistIndex: 1}, ""},
+		{"channel_unique", channel, goutubedl.DownloadOptions{PlaylistIndex: 3}, "v1"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			dr, err := c.result.DownloadWithOptions(context.Background(), c.options)
+			if c.expectedID == "" {
+				if err == nil {
+					dr.Close()
+					t.Fatal("expected error")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			// fake downloads the loaded info JSON, so this is also the entry dict
+			downloaded, err := readFakeDownload(t, dr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if downloaded.ID != c.expectedID || downloaded.Type == "playlist" {
+				t.Errorf("expected entry %s got %s %s", c.expectedID, downloaded.Type, downloaded.ID)
+			}
+		})
+	}
+}
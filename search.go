@@ -0,0 +1,65 @@
+package goutubedl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SearchProvider youtube-dl search prefix
+type SearchProvider string
+
+const (
+	// SearchYouTube YouTube search
+	SearchYouTube SearchProvider = "ytsearch"
+	// SearchYouTubeDate YouTube search, newest first
+	SearchYouTubeDate SearchProvider = "ytsearchdate"
+	// SearchSoundCloud SoundCloud search
+	SearchSoundCloud SearchProvider = "scsearch"
+	// SearchBilibili Bilibili search
+	SearchBilibili SearchProvider = "bilisearch"
+	// SearchNicoNico Niconico search
+	SearchNicoNico SearchProvider = "nicosearch"
+	// SearchNicoNicoDate Niconico search, newest first
+	SearchNicoNicoDate SearchProvider = "nicosearchdate"
+)
+
+// SearchOptions options for Search
+type SearchOptions struct {
+	Options
+	// Number of results to skip, used for pagination
+	Offset uint
+	// Fetch full info for results, default is flat results, see Options.FlatPlaylist
+	Full bool
+}
+
+// Search provider for query. Results are in Info.Entries and limited to limit
+// number of results after options.Offset. Any search prefix supported by
+// youtube-dl can be used as provider.
+func Search(
+	ctx context.Context,
+	provider SearchProvider,
+	query string,
+	limit uint,
+	options SearchOptions,
+) (Result, error) {
+	if provider == "" {
+		return Result{}, errors.New("search provider not set")
+	}
+	if limit == 0 {
+		return Result{}, errors.New("search limit can't be zero")
+	}
+
+	// URL is passed via --batch-file so it has to be one line
+	query = strings.Join(strings.Fields(query), " ")
+	rawURL := fmt.Sprintf("%s%d:%s", provider, options.Offset+limit, query)
+
+	o := options.Options
+	o.Type = TypePlaylist
+	o.FlatPlaylist = !options.Full
+	o.PlaylistStart = options.Offset + 1
+	o.PlaylistEnd = options.Offset + limit
+
+	return New(ctx, rawURL, o)
+}
@@ -0,0 +1,91 @@
+package goutubedl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Section of a media to download, either a time range or chapters with titles
+// matching a regexp. Passed to youtube-dl via --download-sections.
+type Section struct {
+	// Start of time range. Negative is offset from end.
+	Start time.Duration
+	// End of time range. Zero means until end, negative is offset from end.
+	End time.Duration
+	// Regexp matching chapter titles. If set Start and End are ignored.
+	Chapter string
+}
+
+// SectionRange time range section from start to end
+func SectionRange(start, end time.Duration) Section {
+	return Section{Start: start, End: end}
+}
+
+// SectionChapter section of chapters with title matching regexp re
+func SectionChapter(re string) Section {
+	return Section{Chapter: re}
+}
+
+func formatSectionTime(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// String returns section in --download-sections syntax
+func (s Section) String() string {
+	if s.Chapter != "" {
+		return s.Chapter
+	}
+
+	end := "inf"
+	if s.End != 0 {
+		end = formatSectionTime(s.End)
+	}
+
+	return "*" + formatSectionTime(s.Start) + "-" + end
+}
+
+// Validate section time range against media duration in seconds.
+// Chapter regexps can't start with "*" as youtube-dl would parse it as a time
+// range. If duration is unknown (zero) only the ordering of non-relative start
+// and end is checked.
+func (s Section) Validate(duration float64) error {
+	if s.Chapter != "" {
+		if strings.HasPrefix(s.Chapter, "*") {
+			return fmt.Errorf("section %s: chapter regexp can't start with \"*\"", s)
+		}
+		return nil
+	}
+
+	d := time.Duration(duration * float64(time.Second))
+	start, end := s.Start, s.End
+	if d == 0 {
+		if start >= 0 && end > 0 && start >= end {
+			return fmt.Errorf("section %s: start is not before end", s)
+		}
+		return nil
+	}
+
+	if start < 0 {
+		start += d
+	}
+	if end < 0 {
+		end += d
+	} else if end == 0 {
+		end = d
+	}
+
+	switch {
+	case start < 0:
+		return fmt.Errorf("section %s: start is before beginning of %s media", s, d)
+	case start >= d:
+		return fmt.Errorf("section %s: start is after end of %s media", s, d)
+	case end > d:
+		return fmt.Errorf("section %s: end is after end of %s media", s, d)
+	case start >= end:
+		return fmt.Errorf("section %s: start is not before end", s)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package goutubedl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestSectionString(t *testing.T) {
+	for _, c := range []struct {
+		section  goutubedl.Section
+		expected string
+	}{
+		{goutubedl.SectionRange(10*time.Second, 20*time.Second), "*10-20"},
+		{goutubedl.SectionRange(1500*time.Millisecond, 0), "*1.5-inf"},
+		{goutubedl.SectionRange(-5*time.Minute, 0), "*-300-inf"},
+		{goutubedl.SectionRange(0, -10*time.Second), "*0--10"},
+		{goutubedl.SectionChapter("^Intro"), "^Intro"},
+	} {
+		t.Run(c.expected, func(t *testing.T) {
+			if s := c.section.String(); s != c.expected {
+				t.Errorf("expected %q got %q", c.expected, s)
+			}
+		})
+	}
+}
+
+func TestSectionValidate(t *testing.T) {
+	for _, c := range []struct {
+		section  goutubedl.Section
+		duration float64
+		valid    bool
+	}{
+		{goutubedl.SectionRange(10*time.Second, 20*time.Second), 60, true},
+		{goutubedl.SectionRange(10*time.Second, 0), 60, true},
+		{goutubedl.SectionRange(-10*time.Second, 0), 60, true},
+		{goutubedl.SectionRange(0, -10*time.Second), 60, true},
+		{goutubedl.SectionRange(20*time.Second, 10*time.Second), 60, false},
+		{goutubedl.SectionRange(10*time.Second, 70*time.Second), 60, false},
+		{goutubedl.SectionRange(60*time.Second, 0), 60, false},
+		{goutubedl.SectionRange(-70*time.Second, 0), 60, false},
+		{goutubedl.SectionRange(-10*time.Second, -20*time.Second), 60, false},
+		{goutubedl.SectionRange(10*time.Second, 70*time.Second), 0, true},
+		{goutubedl.SectionRange(20*time.Second, 10*time.Second), 0, false},
+		{goutubedl.SectionChapter("Intro"), 60, true},
+		{goutubedl.SectionChapter("*Intro"), 60, false},
+	} {
+		t.Run(c.section.String(), func(t *testing.T) {
+			err := c.section.Validate(c.duration)
+			if c.valid && err != nil {
+				t.Errorf("expected valid got %s", err)
+			} else if !c.valid && err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package goutubedl
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IsStoryboard returns true if format is a storyboard, sprite sheets of
+// preview tiles, ex: youtube "sb0" mhtml formats
+func (f Format) IsStoryboard() bool {
+	return f.FormatNote == "storyboard" || f.Protocol == "mhtml" || f.Ext == "mhtml"
+}
+
+// splitStoryboards moves storyboard formats to Info.Storyboards for info and
+// its entries. Storyboards are also kept in Info.Formats if include is set.
+func splitStoryboards(info *Info, include bool) {
+	var formats []Format
+	info.Storyboards = nil
+	for _, f := range info.Formats {
+		if f.IsStoryboard() {
+			info.Storyboards = append(info.Storyboards, f)
+			if !include {
+				continue
+			}
+		}
+		formats = append(formats, f)
+	}
+	if len(info.Formats) > 0 {
+		info.Formats = formats
+	}
+	for i := range info.Entries {
+		splitStoryboards(&info.Entries[i], include)
+	}
+}
+
+// StoryboardSheet sprite sheet image with Rows times Columns tiles
+type StoryboardSheet struct {
+	URL         string
+	Start       time.Duration
+	Duration    time.Duration
+	Bytes       []byte
+	ContentType string // Detected content type, ex: "image/webp"
+}
+
+// Storyboard sprite sheets with tile layout
+type Storyboard struct {
+	FormatID   string
+	TileWidth  int
+	TileHeight int
+	Rows       int
+	Columns    int
+	Sheets     []StoryboardSheet
+}
+
+// TileAt returns sheet index, row and column of the tile showing time t.
+// Tile duration is from the first sheet as the last sheet might be shorter
+// with fewer tiles.
+func (s Storyboard) TileAt(t time.Duration) (sheet int, row int, column int, ok bool) {
+	tiles := s.Rows * s.Columns
+	if tiles <= 0 || len(s.Sheets) == 0 {
+		return 0, 0, 0, false
+	}
+	tileDuration := s.Sheets[0].Duration / time.Duration(tiles)
+	if tileDuration <= 0 {
+		return 0, 0, 0, false
+	}
+	for i, sh := range s.Sheets {
+		if t < sh.Start || t >= sh.Start+sh.Duration {
+			continue
+		}
+		tile := int((t - sh.Start) / tileDuration)
+		if tile >= tiles {
+			tile = tiles - 1
+		}
+		return i, tile / s.Columns, tile % s.Columns, true
+	}
+	return 0, 0, 0, false
+}
+
+// DownloadStoryboard downloads sprite sheets of storyboard format with
+// formatID, empty formatID is the storyboard with largest tiles. Uses same
+// HTTP client, cookies and fetch options as thumbnails and subtitles.
+func (result Result) DownloadStoryboard(ctx context.Context, formatID string) (Storyboard, error) {
+	var format Format
+	found := false
+	for _, f := range result.Info.Storyboards {
+		if (formatID == "" && (!found || f.Width*f.Height > format.Width*format.Height)) ||
+			(formatID != "" && f.FormatID == formatID) {
+			format = f
+			found = true
+		}
+	}
+	if !found {
+		return Storyboard{}, fmt.Errorf("storyboard format %q not found", formatID)
+	}
+
+	s := Storyboard{
+		FormatID:   format.FormatID,
+		TileWidth:  int(format.Width),
+		TileHeight: int(format.Height),
+		Rows:       format.Rows,
+		Columns:    format.Columns,
+	}
+	var start time.Duration
+	for _, fr := range format.Fragments {
+		d := time.Duration(fr.Duration * float64(time.Second))
+		s.Sheets = append(s.Sheets, StoryboardSheet{URL: fr.URL, Start: start, Duration: d})
+		start += d
+	}
+
+	f, err := newFetcher(result.Options, format.HTTPHeaders)
+	if err != nil {
+		return Storyboard{}, err
+	}
+	var jobs []fetchJob
+	for i := range s.Sheets {
+		sh := &s.Sheets[i]
+		jobs = append(jobs, fetchJob{
+			url: sh.URL,
+			fn: func(b []byte, contentType string) {
+				sh.Bytes = b
+				sh.ContentType = detectImageContentType(b, contentType)
+			},
+		})
+	}
+	if errs := f.fetch(ctx, jobs); len(errs) > 0 {
+		return Storyboard{}, errs[0]
+	}
+
+	return s, nil
+}
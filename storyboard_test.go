@@ -0,0 +1,112 @@
+package goutubedl_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestStoryboardFake(t *testing.T) {
+	useFake(t)
+	var mu sync.Mutex
+	var storyboardHeaders []string
+	fakeHTTPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		storyboardHeaders = append(storyboardHeaders, r.Header.Get("X-Storyboard"))
+		mu.Unlock()
+		_, _ = w.Write([]byte("RIFF\x00\x00\x00\x00WEBP" + r.URL.Path))
+	})
+
+	result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Info.Formats) != 1 || result.Info.Formats[0].FormatID != "f1" {
+		t.Errorf("expected only f1 format got %v", result.Info.Formats)
+	}
+	if len(result.Info.Storyboards) != 2 || !result.Info.Storyboards[0].IsStoryboard() {
+		t.Errorf("expected 2 storyboards got %v", result.Info.Storyboards)
+	}
+
+	includeResult, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{IncludeStoryboards: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(includeResult.Info.Formats) != 3 || len(includeResult.Info.Storyboards) != 2 {
+		t.Errorf("expected 3 formats and 2 storyboards got %v %v", includeResult.Info.Formats, includeResult.Info.Storyboards)
+	}
+
+	if _, err := result.DownloadStoryboard(context.Background(), "sb9"); err == nil {
+		t.Error("expected error for unknown storyboard")
+	}
+
+	// largest tiles by default
+	s, err := result.DownloadStoryboard(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.FormatID != "sb0" || s.TileWidth != 48 || s.TileHeight != 27 || s.Rows != 2 || s.Columns != 2 {
+		t.Errorf("unexpected storyboard %#v", s)
+	}
+	if len(s.Sheets) != 2 {
+		t.Fatalf("expected 2 sheets got %d", len(s.Sheets))
+	}
+	for i, sh := range s.Sheets {
+		if !strings.HasSuffix(string(sh.Bytes), "/sb0/"+string(rune('0'+i))+".webp") || sh.ContentType != "image/webp" {
+			t.Errorf("unexpected sheet %d %q %s", i, sh.Bytes, sh.ContentType)
+		}
+	}
+	if s.Sheets[1].Start != 40*time.Second || s.Sheets[1].Duration != 20*time.Second {
+		t.Errorf("unexpected sheet timing %v %v", s.Sheets[1].Start, s.Sheets[1].Duration)
+	}
+	mu.Lock()
+	if len(storyboardHeaders) != 2 || storyboardHeaders[0] != "sb0" {
+		t.Errorf("expected storyboard http headers got %v", storyboardHeaders)
+	}
+	mu.Unlock()
+
+	for _, c := range []struct {
+		t      time.Duration
+		sheet  int
+		row    int
+		column int
+		ok     bool
+	}{
+		{0, 0, 0, 0, true},
+		{25 * time.Second, 0, 1, 0, true},
+		{39 * time.Second, 0, 1, 1, true},
+		// last sheet is shorter with fewer tiles, tiles are 10s from first sheet
+		{45 * time.Second, 1, 0, 0, true},
+		{59 * time.Second, 1, 0, 1, true},
+		{60 * time.Second, 0, 0, 0, false},
+	} {
+		sheet, row, column, ok := s.TileAt(c.t)
+		if sheet != c.sheet || row != c.row || column != c.column || ok != c.ok {
+			t.Errorf("%s: expected %d %d %d %v got %d %d %d %v", c.t, c.sheet, c.row, c.column, c.ok, sheet, row, column, ok)
+		}
+	}
+}
+
+func TestHeatmapFake(t *testing.T) {
+	useFake(t)
+
+	result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Info.Heatmap) != 2 {
+		t.Fatalf("expected 2 heatmap segments got %v", result.Info.Heatmap)
+	}
+	best, ok := result.Info.MostReplayed()
+	if !ok || best.StartTime != 30 || best.EndTime != 60 || best.Value != 1 {
+		t.Errorf("unexpected most replayed %v %v", best, ok)
+	}
+	if _, ok := (goutubedl.Info{}).MostReplayed(); ok {
+		t.Error("expected no most replayed without heatmap")
+	}
+}
@@ -0,0 +1,48 @@
+package subtitle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type json3 struct {
+	Events []struct {
+		TStartMs    int64 `json:"tStartMs"`
+		DDurationMs int64 `json:"dDurationMs"`
+		Segs        []struct {
+			UTF8 string `json:"utf8"`
+		} `json:"segs"`
+	} `json:"events"`
+}
+
+// ParseJSON3 parses YouTube json3 timedtext. Events without text, ex: window
+// definitions and line breaks in automatic captions, are skipped.
+func ParseJSON3(r io.Reader) ([]Cue, error) {
+	var j json3
+	if err := json.NewDecoder(r).Decode(&j); err != nil {
+		return nil, fmt.Errorf("json3: %w", err)
+	}
+
+	var cues []Cue
+	for _, e := range j.Events {
+		var sb strings.Builder
+		for _, s := range e.Segs {
+			sb.WriteString(s.UTF8)
+		}
+		text := strings.TrimSpace(sb.String())
+		if text == "" {
+			continue
+		}
+		start := time.Duration(e.TStartMs) * time.Millisecond
+		cues = append(cues, Cue{
+			Start: start,
+			End:   start + time.Duration(e.DDurationMs)*time.Millisecond,
+			Text:  text,
+		})
+	}
+
+	return cues, nil
+}
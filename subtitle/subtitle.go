@@ -0,0 +1,257 @@
+// Package subtitle parses and writes subtitles as a list of cues.
+// Supports WebVTT, SRT, TTML and YouTube json3, the formats youtube-dl
+// usually provides, see goutubedl.Subtitle.
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cue text shown from Start to End
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	// Plain text with markup removed, lines are separated by "\n"
+	Text string
+	// WebVTT cue settings, ex: "align:start line:0%". Parsed from TTML
+	// alignment, empty for formats without styling.
+	Styling string
+}
+
+// Format subtitle format, same names as youtube-dl subtitle extensions
+type Format string
+
+// Supported formats
+const (
+	VTT   Format = "vtt"
+	SRT   Format = "srt"
+	TTML  Format = "ttml"
+	JSON3 Format = "json3"
+)
+
+// Parse subtitle in format. Format can also be an alias like "dfxp" for TTML.
+func Parse(format Format, r io.Reader) ([]Cue, error) {
+	switch format {
+	case VTT:
+		return ParseVTT(r)
+	case SRT:
+		return ParseSRT(r)
+	case TTML, "dfxp", "xml":
+		return ParseTTML(r)
+	case JSON3:
+		return ParseJSON3(r)
+	default:
+		return nil, fmt.Errorf("unsupported subtitle format %q", format)
+	}
+}
+
+// Write cues in format, only VTT and SRT are supported
+func Write(format Format, w io.Writer, cues []Cue) error {
+	switch format {
+	case VTT:
+		return WriteVTT(w, cues)
+	case SRT:
+		return WriteSRT(w, cues)
+	default:
+		return fmt.Errorf("unsupported subtitle write format %q", format)
+	}
+}
+
+// [[hh:]mm:]ss[.,]fff
+var timestampRe = regexp.MustCompile(`^(?:(?:(\d+):)?(\d+):)?(\d+)(?:[.,](\d{1,3}))?$`)
+
+func parseTimestamp(s string) (time.Duration, error) {
+	m := timestampRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	// regexp only matches digits
+	var ns [3]int
+	for i, part := range m[1:4] {
+		ns[i], _ = strconv.Atoi(part)
+	}
+	ms := 0
+	if m[4] != "" {
+		// "5" is 500ms
+		ms, _ = strconv.Atoi((m[4] + "00")[0:3])
+	}
+	return time.Duration(ns[0])*time.Hour +
+		time.Duration(ns[1])*time.Minute +
+		time.Duration(ns[2])*time.Second +
+		time.Duration(ms)*time.Millisecond, nil
+}
+
+func formatTimestamp(d time.Duration, msSep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d",
+		ms/3600000, ms/60000%60, ms/1000%60, msSep, ms%1000,
+	)
+}
+
+// parseTiming parses "start --> end settings"
+func parseTiming(line string) (start time.Duration, end time.Duration, settings string, err error) {
+	parts := strings.SplitN(line, "-->", 2)
+	if len(parts) != 2 {
+		return 0, 0, "", fmt.Errorf("invalid timing %q", line)
+	}
+	if start, err = parseTimestamp(parts[0]); err != nil {
+		return 0, 0, "", err
+	}
+	endAndSettings := strings.Fields(parts[1])
+	if len(endAndSettings) == 0 {
+		return 0, 0, "", fmt.Errorf("invalid timing %q", line)
+	}
+	if end, err = parseTimestamp(endAndSettings[0]); err != nil {
+		return 0, 0, "", err
+	}
+	return start, end, strings.Join(endAndSettings[1:], " "), nil
+}
+
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+// plainText removes tags like <i> and <c.color> and unescapes entities
+func plainText(lines []string) string {
+	return html.UnescapeString(tagRe.ReplaceAllString(strings.Join(lines, "\n"), ""))
+}
+
+// blocks returns blank line separated blocks of lines
+func blocks(r io.Reader) ([][]string, error) {
+	var bs [][]string
+	var b []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+		if strings.TrimSpace(line) == "" {
+			if len(b) > 0 {
+				bs = append(bs, b)
+				b = nil
+			}
+			continue
+		}
+		b = append(b, line)
+	}
+	if len(b) > 0 {
+		bs = append(bs, b)
+	}
+	return bs, scanner.Err()
+}
+
+// ParseVTT parses WebVTT
+func ParseVTT(r io.Reader) ([]Cue, error) {
+	bs, err := blocks(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 || !strings.HasPrefix(bs[0][0], "WEBVTT") {
+		return nil, fmt.Errorf("vtt: missing WEBVTT header")
+	}
+
+	var cues []Cue
+	for _, b := range bs[1:] {
+		switch {
+		case strings.HasPrefix(b[0], "NOTE"),
+			strings.HasPrefix(b[0], "STYLE"),
+			strings.HasPrefix(b[0], "REGION"):
+			continue
+		}
+		// optional cue identifier
+		if !strings.Contains(b[0], "-->") {
+			b = b[1:]
+		}
+		if len(b) == 0 {
+			continue
+		}
+		start, end, settings, err := parseTiming(b[0])
+		if err != nil {
+			return nil, fmt.Errorf("vtt: %w", err)
+		}
+		cues = append(cues, Cue{
+			Start:   start,
+			End:     end,
+			Text:    plainText(b[1:]),
+			Styling: settings,
+		})
+	}
+
+	return cues, nil
+}
+
+// ParseSRT parses SubRip
+func ParseSRT(r io.Reader) ([]Cue, error) {
+	bs, err := blocks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cues []Cue
+	for _, b := range bs {
+		// index line
+		if !strings.Contains(b[0], "-->") {
+			b = b[1:]
+		}
+		if len(b) == 0 {
+			continue
+		}
+		start, end, _, err := parseTiming(b[0])
+		if err != nil {
+			return nil, fmt.Errorf("srt: %w", err)
+		}
+		cues = append(cues, Cue{
+			Start: start,
+			End:   end,
+			Text:  plainText(b[1:]),
+		})
+	}
+
+	return cues, nil
+}
+
+var vttTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// WriteVTT writes cues as WebVTT
+func WriteVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n")
+	for _, c := range cues {
+		bw.WriteString("\n")
+		bw.WriteString(formatTimestamp(c.Start, ".") + " --> " + formatTimestamp(c.End, "."))
+		if c.Styling != "" {
+			bw.WriteString(" " + c.Styling)
+		}
+		bw.WriteString("\n")
+		bw.WriteString(vttTextEscaper.Replace(c.Text))
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// WriteSRT writes cues as SubRip
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range cues {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, "%d\n", i+1)
+		bw.WriteString(formatTimestamp(c.Start, ",") + " --> " + formatTimestamp(c.End, ",") + "\n")
+		bw.WriteString(c.Text)
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
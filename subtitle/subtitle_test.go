@@ -0,0 +1,151 @@
+package subtitle_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl/subtitle"
+)
+
+func ms(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		format   subtitle.Format
+		s        string
+		expected []subtitle.Cue
+	}{
+		{
+			"vtt",
+			subtitle.VTT,
+			"\ufeffWEBVTT\nKind: captions\n\nNOTE a comment\n\n" +
+				"1\n00:00:01.000 --> 00:00:02.500 align:start position:0%\nHello <c.colorE5E5E5>world</c>\n\n" +
+				"01:02.5 --> 01:03.000\nline 1\nline &amp; 2\n",
+			[]subtitle.Cue{
+				{Start: ms(1000), End: ms(2500), Text: "Hello world", Styling: "align:start position:0%"},
+				{Start: ms(62500), End: ms(63000), Text: "line 1\nline & 2"},
+			},
+		},
+		{
+			"srt",
+			subtitle.SRT,
+			"1\r\n00:00:01,000 --> 00:00:02,500\r\n<i>Hello</i> world\r\n\r\n2\r\n01:00:00,001 --> 01:00:01,000\r\nline 1\r\nline 2\r\n",
+			[]subtitle.Cue{
+				{Start: ms(1000), End: ms(2500), Text: "Hello world"},
+				{Start: time.Hour + ms(1), End: time.Hour + ms(1000), Text: "line 1\nline 2"},
+			},
+		},
+		{
+			"ttml",
+			subtitle.TTML,
+			`<?xml version="1.0" encoding="utf-8" ?>
+<tt xmlns="http://www.w3.org/ns/ttml" xmlns:tts="http://www.w3.org/ns/ttml#styling" xmlns:ttp="http://www.w3.org/ns/ttml#parameter" ttp:tickRate="10000000">
+<body><div>
+<p begin="00:00:01.000" end="00:00:02.500" tts:textAlign="center">Hello <span>world</span><br/>
+  line 2</p>
+<p begin="30000000t" dur="1.5s">tick</p>
+<p begin="00:00:05:15" end="00:00:06.000">frames</p>
+</div></body>
+</tt>`,
+			[]subtitle.Cue{
+				{Start: ms(1000), End: ms(2500), Text: "Hello world\nline 2", Styling: "align:center"},
+				{Start: ms(3000), End: ms(4500), Text: "tick"},
+				{Start: ms(5500), End: ms(6000), Text: "frames"},
+			},
+		},
+		{
+			"json3",
+			subtitle.JSON3,
+			`{"wireMagic":"pb3","events":[
+{"tStartMs":0,"dDurationMs":5000,"id":1,"wpWinPosId":1},
+{"tStartMs":1000,"dDurationMs":1500,"wWinId":1,"segs":[{"utf8":"Hello"},{"utf8":" world","tOffsetMs":500}]},
+{"tStartMs":2500,"dDurationMs":10,"aAppend":1,"segs":[{"utf8":"\n"}]}
+]}`,
+			[]subtitle.Cue{
+				{Start: ms(1000), End: ms(2500), Text: "Hello world"},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			cues, err := subtitle.Parse(c.format, strings.NewReader(c.s))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.expected, cues) {
+				t.Errorf("expected:\n%#v\ngot:\n%#v", c.expected, cues)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, c := range []struct {
+		format subtitle.Format
+		s      string
+	}{
+		{subtitle.VTT, "00:00:01.000 --> 00:00:02.000\nno header\n"},
+		{subtitle.VTT, "WEBVTT\n\n00:00:aa.000 --> 00:00:02.000\nbad timestamp\n"},
+		{subtitle.SRT, "1\n00:00:01,000 -> 00:00:02,000\nbad arrow\n"},
+		{subtitle.TTML, "<tt><body><p begin=\"bad\">a</p></body></tt>"},
+		{subtitle.JSON3, "{"},
+		{"ass", ""},
+	} {
+		t.Run(string(c.format), func(t *testing.T) {
+			if _, err := subtitle.Parse(c.format, strings.NewReader(c.s)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	cues := []subtitle.Cue{
+		{Start: ms(1000), End: ms(2500), Text: "Hello <world> & you", Styling: "align:start"},
+		{Start: time.Hour + ms(62001), End: time.Hour + ms(63000), Text: "line 1\nline 2"},
+	}
+
+	for _, c := range []struct {
+		format   subtitle.Format
+		expected string
+	}{
+		{
+			subtitle.VTT,
+			"WEBVTT\n\n" +
+				"00:00:01.000 --> 00:00:02.500 align:start\nHello &lt;world&gt; &amp; you\n\n" +
+				"01:01:02.001 --> 01:01:03.000\nline 1\nline 2\n",
+		},
+		{
+			subtitle.SRT,
+			"1\n00:00:01,000 --> 00:00:02,500\nHello <world> & you\n\n" +
+				"2\n01:01:02,001 --> 01:01:03,000\nline 1\nline 2\n",
+		},
+	} {
+		t.Run(string(c.format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := subtitle.Write(c.format, buf, cues); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != c.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", c.expected, buf.String())
+			}
+
+			// round trip, srt has no styling and "<world>" is parsed as a tag
+			parsed, err := subtitle.Parse(c.format, buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedCues := append([]subtitle.Cue(nil), cues...)
+			if c.format == subtitle.SRT {
+				expectedCues[0].Styling = ""
+				expectedCues[0].Text = "Hello  & you"
+			}
+			if !reflect.DeepEqual(expectedCues, parsed) {
+				t.Errorf("expected:\n%#v\ngot:\n%#v", expectedCues, parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package subtitle
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ttmlWhitespaceRe = regexp.MustCompile(`\s+`)
+
+type ttmlTiming struct {
+	frameRate float64
+	tickRate  float64
+}
+
+// offset time like "1.5s", "100ms" or "10t"
+var ttmlOffsetRe = regexp.MustCompile(`^([\d.]+)(h|m|s|ms|f|t)$`)
+
+// parseTime parses TTML time expression, clock time like "00:00:01.500" or
+// "00:00:01:15" (frames) or offset time
+func (t ttmlTiming) parseTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if m := ttmlOffsetRe.FindStringSubmatch(s); m != nil {
+		f, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		var seconds float64
+		switch m[2] {
+		case "h":
+			seconds = f * 3600
+		case "m":
+			seconds = f * 60
+		case "s":
+			seconds = f
+		case "ms":
+			seconds = f / 1000
+		case "f":
+			seconds = f / t.frameRate
+		case "t":
+			seconds = f / t.tickRate
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) == 4 {
+		frames, err := strconv.ParseFloat(parts[3], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		d, err := parseTimestamp(strings.Join(parts[0:3], ":"))
+		if err != nil {
+			return 0, err
+		}
+		return d + time.Duration(frames/t.frameRate*float64(time.Second)), nil
+	}
+
+	return parseTimestamp(s)
+}
+
+func attr(se xml.StartElement, local string) (string, bool) {
+	for _, a := range se.Attr {
+		if a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// ParseTTML parses TTML (also known as DFXP). Paragraphs with begin and end
+// or dur attributes are cues, tts:textAlign is used as cue alignment.
+func ParseTTML(r io.Reader) ([]Cue, error) {
+	timing := ttmlTiming{frameRate: 30, tickRate: 1}
+	d := xml.NewDecoder(r)
+
+	var cues []Cue
+	var cue *Cue
+	var text strings.Builder
+	depth := 0
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("ttml: %w", err)
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			if cue != nil {
+				depth++
+				if t.Name.Local == "br" {
+					text.WriteString("\n")
+				}
+				continue
+			}
+			switch t.Name.Local {
+			case "tt":
+				if v, ok := attr(t, "frameRate"); ok {
+					if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+						timing.frameRate = f
+					}
+				}
+				if v, ok := attr(t, "tickRate"); ok {
+					if f, err := strconv.ParseFloat(v, 64); err == nil && f > 0 {
+						timing.tickRate = f
+					}
+				}
+			case "p":
+				begin, hasBegin := attr(t, "begin")
+				if !hasBegin {
+					continue
+				}
+				start, err := timing.parseTime(begin)
+				if err != nil {
+					return nil, fmt.Errorf("ttml: %w", err)
+				}
+				var end time.Duration
+				if v, ok := attr(t, "end"); ok {
+					if end, err = timing.parseTime(v); err != nil {
+						return nil, fmt.Errorf("ttml: %w", err)
+					}
+				} else if v, ok := attr(t, "dur"); ok {
+					dur, err := timing.parseTime(v)
+					if err != nil {
+						return nil, fmt.Errorf("ttml: %w", err)
+					}
+					end = start + dur
+				}
+				cue = &Cue{Start: start, End: end}
+				if v, ok := attr(t, "textAlign"); ok {
+					cue.Styling = "align:" + v
+				}
+				text.Reset()
+				depth = 0
+			}
+		case xml.CharData:
+			if cue != nil {
+				// whitespace including newlines collapse, line breaks are <br/>
+				text.WriteString(ttmlWhitespaceRe.ReplaceAllString(string(t), " "))
+			}
+		case xml.EndElement:
+			if cue == nil {
+				continue
+			}
+			if depth > 0 {
+				depth--
+				continue
+			}
+			lines := strings.Split(text.String(), "\n")
+			for i, l := range lines {
+				lines[i] = strings.TrimSpace(l)
+			}
+			cue.Text = strings.Join(lines, "\n")
+			cues = append(cues, *cue)
+			cue = nil
+		}
+	}
+
+	return cues, nil
+}
@@ -0,0 +1,129 @@
+package goutubedl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// subtitleArgs returns youtube-dl arguments for subtitle options
+func (options Options) subtitleArgs() []string {
+	langs := options.SubtitleLanguages
+	if len(langs) == 0 {
+		langs = []string{"all"}
+	}
+	args := []string{"--sub-langs", strings.Join(langs, ",")}
+	if len(options.SubtitleFormats) > 0 {
+		args = append(args, "--sub-format", strings.Join(options.SubtitleFormats, "/"))
+	}
+	if options.AutomaticCaptions {
+		args = append(args, "--write-auto-subs")
+	}
+	return args
+}
+
+// matchSubtitleLanguages returns languages selected by patterns, same
+// semantics as --sub-langs. Empty patterns selects all languages.
+func matchSubtitleLanguages(patterns []string, languages []string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	if len(patterns) == 0 {
+		patterns = []string{"all"}
+	}
+
+	for _, p := range patterns {
+		discard := strings.HasPrefix(p, "-")
+		if discard {
+			p = p[1:]
+		}
+		if p == "all" {
+			for _, l := range languages {
+				selected[l] = !discard
+			}
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("subtitle language %q: %w", p, err)
+		}
+		for _, l := range languages {
+			if re.MatchString(l) {
+				selected[l] = !discard
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+// selectSubtitleFormat returns indexes of subtitles to fetch. If formats is
+// empty all are selected, otherwise first available preferred format or
+// youtube-dl's best (last) format if none is available.
+func selectSubtitleFormat(formats []string, subtitles []Subtitle) []int {
+	if len(subtitles) == 0 {
+		return nil
+	}
+	if len(formats) == 0 {
+		is := make([]int, len(subtitles))
+		for i := range subtitles {
+			is[i] = i
+		}
+		return is
+	}
+	for _, f := range formats {
+		for i, s := range subtitles {
+			if s.Ext == f {
+				return []int{i}
+			}
+		}
+	}
+	return []int{len(subtitles) - 1}
+}
+
+// selectedSubtitles returns subtitles to fetch in info selected by options.
+// Automatic captions are only used for languages without subtitles, same as
+// youtube-dl.
+func (options Options) selectedSubtitles(info *Info) ([]*Subtitle, error) {
+	var languages []string
+	for l := range info.Subtitles {
+		languages = append(languages, l)
+	}
+	if options.AutomaticCaptions {
+		for l := range info.AutomaticCaptions {
+			if _, ok := info.Subtitles[l]; !ok {
+				languages = append(languages, l)
+			}
+		}
+	}
+	sort.Strings(languages)
+
+	selected, err := matchSubtitleLanguages(options.SubtitleLanguages, languages)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []*Subtitle
+	for _, l := range languages {
+		if !selected[l] {
+			continue
+		}
+		s, ok := info.Subtitles[l]
+		if !ok {
+			s = info.AutomaticCaptions[l]
+		}
+		for _, i := range selectSubtitleFormat(options.SubtitleFormats, s) {
+			subs = append(subs, &s[i])
+		}
+	}
+
+	return subs, nil
+}
+
+// isHTTP returns true if subtitle can be fetched with a HTTP GET of its URL
+func (s Subtitle) isHTTP() bool {
+	switch s.Protocol {
+	case "", "http", "https":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package goutubedl_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+// fakeHTTPServer serves request path and query as body, used as fake side asset server.
+// handler is used instead if not nil.
+func fakeHTTPServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	if handler == nil {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.URL.RequestURI()))
+		}
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	os.Setenv(fakeHTTPEnv, ts.URL)
+	return ts
+}
+
+func TestSubtitlesFake(t *testing.T) {
+	useFake(t)
+	fakeHTTPServer(t, nil)
+
+	for _, c := range []struct {
+		name         string
+		options      goutubedl.Options
+		expectedArgs string
+		expected     []string
+	}{
+		{
+			"default",
+			goutubedl.Options{},
+			"--sub-langs all",
+			[]string{"/sub/en.srt", "/sub/en.vtt", "/sub/sv.vtt"},
+		},
+		{
+			"langs",
+			goutubedl.Options{SubtitleLanguages: []string{"all", "-live_chat", "-s.*"}},
+			"--sub-langs all,-live_chat,-s.*",
+			[]string{"/sub/en.srt", "/sub/en.vtt"},
+		},
+		{
+			"formats",
+			goutubedl.Options{SubtitleFormats: []string{"srt", "vtt"}},
+			"--sub-langs all --sub-format srt/vtt",
+			[]string{"/sub/en.srt", "/sub/sv.vtt"},
+		},
+		{
+			"automatic",
+			goutubedl.Options{
+				SubtitleLanguages: []string{"en", "de"},
+				SubtitleFormats:   []string{"vtt"},
+				AutomaticCaptions: true,
+			},
+			"--sub-langs en,de --sub-format vtt --write-auto-subs",
+			[]string{"/sub/de.vtt?auto", "/sub/en.vtt"},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			options := c.options
+			options.DownloadSubtitles = true
+			result, err := goutubedl.New(context.Background(), fakeSingleURL, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if args := strings.Join(fakeArgs(t, result), " "); !strings.Contains(args, c.expectedArgs) {
+				t.Errorf("expected %q in args %q", c.expectedArgs, args)
+			}
+
+			var fetched []string
+			for _, subs := range []map[string][]goutubedl.Subtitle{result.Info.Subtitles, result.Info.AutomaticCaptions} {
+				for lang, ss := range subs {
+					for _, s := range ss {
+						if s.Language != lang {
+							t.Errorf("expected language %s got %s", lang, s.Language)
+						}
+						if s.Automatic != strings.HasSuffix(s.URL, "?auto") {
+							t.Errorf("%s: unexpected automatic %v", s.URL, s.Automatic)
+						}
+						if len(s.Bytes) > 0 {
+							fetched = append(fetched, string(s.Bytes))
+						}
+					}
+				}
+			}
+			sort.Strings(fetched)
+			if !reflect.DeepEqual(c.expected, fetched) {
+				t.Errorf("expected %v got %v", c.expected, fetched)
+			}
+		})
+	}
+}
+
+func TestSubtitlesInvalidLanguage(t *testing.T) {
+	_, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{
+		DownloadSubtitles: true,
+		SubtitleLanguages: []string{"en("},
+	})
+	if err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,168 @@
+package goutubedl
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg" // register for image.DecodeConfig
+	_ "image/png"  // register for image.DecodeConfig
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ThumbnailSelector selects thumbnails in Info.Thumbnails. Thumbnails with
+// unknown width or height don't match if a size or aspect ratio is set.
+type ThumbnailSelector struct {
+	MinWidth  int
+	MaxWidth  int
+	MinHeight int
+	MaxHeight int
+	// Width divided by height, ex: 16.0 / 9. Zero is any aspect ratio.
+	AspectRatio float64
+	// Allowed difference from AspectRatio (default 0.01)
+	AspectRatioTolerance float64
+	// Thumbnail IDs to select, empty is any
+	IDs []string
+	// Select all matching thumbnails instead of only the best one
+	All bool
+}
+
+func (s ThumbnailSelector) isZero() bool {
+	return s.MinWidth == 0 && s.MaxWidth == 0 &&
+		s.MinHeight == 0 && s.MaxHeight == 0 &&
+		s.AspectRatio == 0 && len(s.IDs) == 0 && !s.All
+}
+
+// Match returns true if thumbnail matches size, aspect ratio and IDs
+func (s ThumbnailSelector) Match(t Thumbnail) bool {
+	if len(s.IDs) > 0 {
+		found := false
+		for _, id := range s.IDs {
+			found = found || id == t.ID
+		}
+		if !found {
+			return false
+		}
+	}
+
+	sized := s.MinWidth > 0 || s.MaxWidth > 0 || s.MinHeight > 0 || s.MaxHeight > 0 || s.AspectRatio > 0
+	if !sized {
+		return true
+	}
+	if t.Width <= 0 || t.Height <= 0 {
+		return false
+	}
+	if (s.MinWidth > 0 && t.Width < s.MinWidth) ||
+		(s.MaxWidth > 0 && t.Width > s.MaxWidth) ||
+		(s.MinHeight > 0 && t.Height < s.MinHeight) ||
+		(s.MaxHeight > 0 && t.Height > s.MaxHeight) {
+		return false
+	}
+	if s.AspectRatio > 0 {
+		tolerance := s.AspectRatioTolerance
+		if tolerance == 0 {
+			tolerance = 0.01
+		}
+		if math.Abs(float64(t.Width)/float64(t.Height)-s.AspectRatio) > tolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// selectIndexes returns indexes of matching thumbnails best first. Best is
+// highest preference then largest size, same as youtube-dl thumbnails are
+// sorted with best last.
+func (s ThumbnailSelector) selectIndexes(thumbnails []Thumbnail) []int {
+	var is []int
+	for i, t := range thumbnails {
+		if s.Match(t) {
+			is = append(is, i)
+		}
+	}
+	sort.SliceStable(is, func(a, b int) bool {
+		ta, tb := thumbnails[is[a]], thumbnails[is[b]]
+		if ta.Preference != tb.Preference {
+			return ta.Preference > tb.Preference
+		}
+		if ta.Width*ta.Height != tb.Width*tb.Height {
+			return ta.Width*ta.Height > tb.Width*tb.Height
+		}
+		return is[a] > is[b]
+	})
+	if !s.All && len(is) > 1 {
+		is = is[:1]
+	}
+	return is
+}
+
+// SelectThumbnails returns thumbnails matching s, best first. Only the best
+// is returned unless s.All is set.
+func (info Info) SelectThumbnails(s ThumbnailSelector) []Thumbnail {
+	var ts []Thumbnail
+	for _, i := range s.selectIndexes(info.Thumbnails) {
+		ts = append(ts, info.Thumbnails[i])
+	}
+	return ts
+}
+
+// thumbnailJobs returns fetch jobs for thumbnails selected by s. Best selected
+// thumbnail is also stored in Info.ThumbnailBytes.
+func thumbnailJobs(info *Info, s ThumbnailSelector) []fetchJob {
+	var is []int
+	if s.isZero() {
+		if info.Thumbnail == "" {
+			return nil
+		}
+		for i, t := range info.Thumbnails {
+			if t.URL == info.Thumbnail {
+				is = []int{i}
+			}
+		}
+		// thumbnail not in thumbnails list
+		if len(is) == 0 {
+			return []fetchJob{{
+				url: info.Thumbnail,
+				fn:  func(b []byte, _ string) { info.ThumbnailBytes = b },
+			}}
+		}
+	} else {
+		is = s.selectIndexes(info.Thumbnails)
+	}
+
+	var jobs []fetchJob
+	for n, i := range is {
+		t := &info.Thumbnails[i]
+		best := n == 0
+		jobs = append(jobs, fetchJob{
+			url: t.URL,
+			fn: func(b []byte, contentType string) {
+				t.Bytes = b
+				t.ContentType = detectImageContentType(b, contentType)
+				if best {
+					info.ThumbnailBytes = b
+				}
+			},
+		})
+	}
+
+	return jobs
+}
+
+// detectImageContentType detects JPEG, PNG and WebP, otherwise uses the HTTP
+// content type or sniffs it
+func detectImageContentType(b []byte, contentType string) string {
+	if _, format, err := image.DecodeConfig(bytes.NewReader(b)); err == nil {
+		return "image/" + format
+	}
+	// RIFF <size> WEBP
+	if len(b) >= 12 && string(b[0:4]) == "RIFF" && string(b[8:12]) == "WEBP" {
+		return "image/webp"
+	}
+	if contentType != "" && !strings.HasPrefix(contentType, "application/octet-stream") {
+		return contentType
+	}
+	return http.DetectContentType(b)
+}
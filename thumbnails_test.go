@@ -0,0 +1,107 @@
+package goutubedl_test
+
+import (
+	"context"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/wader/goutubedl"
+)
+
+func TestThumbnailsFake(t *testing.T) {
+	useFake(t)
+	fakeHTTPServer(t, func(w http.ResponseWriter, r *http.Request) {
+		img := image.NewGray(image.Rect(0, 0, 2, 2))
+		// wrong content type to make sure content is detected
+		w.Header().Set("Content-Type", "application/octet-stream")
+		switch path.Ext(r.URL.Path) {
+		case ".jpg":
+			_ = jpeg.Encode(w, img, nil)
+		case ".png":
+			_ = png.Encode(w, img)
+		case ".webp":
+			_, _ = w.Write([]byte("RIFF\x00\x00\x00\x00WEBPVP8 "))
+		}
+	})
+
+	for _, c := range []struct {
+		name      string
+		selector  goutubedl.ThumbnailSelector
+		expected  map[string]string
+		bestBytes bool
+	}{
+		{
+			name:     "default",
+			expected: map[string]string{},
+		},
+		{
+			name:      "all",
+			selector:  goutubedl.ThumbnailSelector{All: true},
+			expected:  map[string]string{"small": "image/jpeg", "medium": "image/png", "large": "image/webp", "unsized": "image/jpeg"},
+			bestBytes: true,
+		},
+		{
+			name:      "max_width",
+			selector:  goutubedl.ThumbnailSelector{MaxWidth: 500},
+			expected:  map[string]string{"medium": "image/png"},
+			bestBytes: true,
+		},
+		{
+			name:      "aspect_ratio",
+			selector:  goutubedl.ThumbnailSelector{AspectRatio: 4.0 / 3, All: true},
+			expected:  map[string]string{"small": "image/jpeg", "medium": "image/png"},
+			bestBytes: true,
+		},
+		{
+			name:      "ids",
+			selector:  goutubedl.ThumbnailSelector{IDs: []string{"unsized", "large"}, All: true},
+			expected:  map[string]string{"large": "image/webp", "unsized": "image/jpeg"},
+			bestBytes: true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := goutubedl.New(context.Background(), fakeSingleURL, goutubedl.Options{
+				DownloadThumbnail: true,
+				Thumbnails:        c.selector,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.AssetErrors) > 0 {
+				t.Fatal(result.AssetErrors)
+			}
+
+			actual := map[string]string{}
+			for _, th := range result.Info.Thumbnails {
+				if len(th.Bytes) > 0 {
+					actual[th.ID] = th.ContentType
+				}
+			}
+			if !reflect.DeepEqual(c.expected, actual) {
+				t.Errorf("expected %v got %v", c.expected, actual)
+			}
+
+			// default fetches Info.Thumbnail which is not in thumbnails
+			if len(result.Info.ThumbnailBytes) == 0 {
+				t.Error("expected thumbnail bytes")
+			}
+			if c.bestBytes {
+				best := result.Info.SelectThumbnails(c.selector)[0]
+				var bestBytes []byte
+				for _, th := range result.Info.Thumbnails {
+					if th.ID == best.ID {
+						bestBytes = th.Bytes
+					}
+				}
+				if !reflect.DeepEqual(bestBytes, result.Info.ThumbnailBytes) {
+					t.Errorf("expected thumbnail bytes to be best thumbnail %s", best.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package goutubedl
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"time"
+)
+
+// WatchEvent new entry found by Watcher or error when polling URL
+type WatchEvent struct {
+	URL   string
+	Entry Info
+	Err   error
+}
+
+// WatcherOptions options for Watcher
+type WatcherOptions struct {
+	// Options used for flat extraction. Type defaults to TypeChannel.
+	Options
+	// Poll interval (default 5 minutes)
+	Interval time.Duration
+	// Fraction of interval to randomly add or subtract (default 0.1)
+	Jitter float64
+	// Max interval when backing off on errors (default 1 hour)
+	MaxBackoff time.Duration
+	// Number of entries fetched per page (default 50)
+	PageSize uint
+	// Store for seen entries (default in-memory)
+	Seen ArchiveStore
+	// Only mark entries found on first poll of a URL as seen without emitting
+	// events, useful when starting to watch with an empty store
+	SkipInitial bool
+}
+
+// Watcher polls playlist or channel URLs and emits events for new entries.
+// Entries are expected to be ordered newest first, as polling of a URL stops
+// at the first already seen entry.
+type Watcher struct {
+	urls    []string
+	options WatcherOptions
+}
+
+// NewWatcher returns a new Watcher for urls
+func NewWatcher(urls []string, options WatcherOptions) *Watcher {
+	if options.Type == TypeAny {
+		options.Type = TypeChannel
+	}
+	if options.Interval == 0 {
+		options.Interval = 5 * time.Minute
+	}
+	if options.Jitter == 0 {
+		options.Jitter = 0.1
+	}
+	if options.MaxBackoff == 0 {
+		options.MaxBackoff = time.Hour
+	}
+	if options.PageSize == 0 {
+		options.PageSize = 50
+	}
+	if options.Seen == nil {
+		options.Seen = NewMemoryArchive()
+	}
+	if options.DebugLog == nil {
+		options.DebugLog = nopPrinter{}
+	}
+	options.FlatPlaylist = true
+
+	return &Watcher{
+		urls:    urls,
+		options: options,
+	}
+}
+
+func (w *Watcher) jitter(d time.Duration) time.Duration {
+	return d + time.Duration((rand.Float64()*2-1)*w.options.Jitter*float64(d))
+}
+
+// Watch starts polling all URLs and returns a channel with events. New
+// entries for a URL are emitted oldest first and marked as seen after being
+// received. Channel is closed when ctx is done.
+func (w *Watcher) Watch(ctx context.Context) <-chan WatchEvent {
+	eventCh := make(chan WatchEvent)
+
+	doneCh := make(chan struct{})
+	for _, u := range w.urls {
+		go func(rawURL string) {
+			w.watchURL(ctx, rawURL, eventCh)
+			doneCh <- struct{}{}
+		}(u)
+	}
+	go func() {
+		for range w.urls {
+			<-doneCh
+		}
+		close(eventCh)
+	}()
+
+	return eventCh
+}
+
+func (w *Watcher) watchURL(ctx context.Context, rawURL string, eventCh chan<- WatchEvent) {
+	first := true
+	backoff := time.Duration(0)
+	delay := time.Duration(0)
+
+	for {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		entries, err := w.poll(ctx, rawURL, first && w.options.SkipInitial)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case eventCh <- WatchEvent{URL: rawURL, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			if backoff == 0 {
+				backoff = w.options.Interval
+			}
+			backoff *= 2
+			if backoff > w.options.MaxBackoff {
+				backoff = w.options.MaxBackoff
+			}
+			delay = w.jitter(backoff)
+			continue
+		}
+		first = false
+		backoff = 0
+		delay = w.jitter(w.options.Interval)
+
+		for i := len(entries) - 1; i >= 0; i-- {
+			select {
+			case eventCh <- WatchEvent{URL: rawURL, Entry: entries[i]}:
+			case <-ctx.Done():
+				return
+			}
+			if err := w.options.Seen.Add(entries[i].ArchiveID()); err != nil {
+				w.options.DebugLog.Print("watcher ", rawURL, ": ", err)
+			}
+		}
+	}
+}
+
+// poll returns unseen entries, newest first, until the first seen entry.
+// If markOnly first page of entries are marked as seen and not returned.
+func (w *Watcher) poll(ctx context.Context, rawURL string, markOnly bool) ([]Info, error) {
+	pager := NewPager(rawURL, w.options.Options, w.options.PageSize)
+	var entries []Info
+
+	for {
+		r, err := pager.Next(ctx)
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if markOnly {
+			for _, e := range r.Info.Entries {
+				if key := e.ArchiveID(); key != "" {
+					if err := w.options.Seen.Add(key); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return nil, nil
+		}
+
+		for _, e := range r.Info.Entries {
+			key := e.ArchiveID()
+			if key == "" {
+				continue
+			}
+			seen, err := w.options.Seen.Has(key)
+			if err != nil {
+				return nil, err
+			} else if seen {
+				return entries, nil
+			}
+			entries = append(entries, e)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package goutubedl_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/wader/goutubedl"
+)
+
+func nextWatchEvent(t *testing.T, eventCh <-chan goutubedl.WatchEvent) goutubedl.WatchEvent {
+	t.Helper()
+	select {
+	case ev := <-eventCh:
+		return ev
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for watch event")
+		return goutubedl.WatchEvent{}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	useFake(t)
+
+	os.Setenv(fakeCountEnv, "3")
+	seen := goutubedl.NewMemoryArchive()
+	w := goutubedl.NewWatcher([]string{fakePlaylistURL}, goutubedl.WatcherOptions{
+		Seen:        seen,
+		Interval:    50 * time.Millisecond,
+		PageSize:    2,
+		SkipInitial: true,
+	})
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	eventCh := w.Watch(ctx)
+
+	// wait for initial poll to mark entries as seen
+	for i := 0; ; i++ {
+		if ok, _ := seen.Has(goutubedl.Info{ID: "e3", IEKey: "Fake"}.ArchiveID()); ok {
+			break
+		}
+		if i > 200 {
+			t.Fatal("timeout waiting for initial poll")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	os.Setenv(fakeCountEnv, "5")
+	for _, expectedID := range []string{"e4", "e5"} {
+		ev := nextWatchEvent(t, eventCh)
+		if ev.Err != nil {
+			t.Fatal(ev.Err)
+		}
+		if ev.URL != fakePlaylistURL || ev.Entry.ID != expectedID {
+			t.Errorf("expected %s %s got %s %s", fakePlaylistURL, expectedID, ev.URL, ev.Entry.ID)
+		}
+	}
+
+	cancelFn()
+	for ev := range eventCh {
+		if ev.Err == nil {
+			t.Errorf("expected no more new entries got %s", ev.Entry.ID)
+		}
+	}
+}
+
+func TestWatcherError(t *testing.T) {
+	useFake(t)
+
+	w := goutubedl.NewWatcher([]string{"fake://unsupported"}, goutubedl.WatcherOptions{
+		Interval: time.Hour,
+	})
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+	eventCh := w.Watch(ctx)
+
+	ev := nextWatchEvent(t, eventCh)
+	if ev.Err == nil {
+		t.Errorf("expected error event got entry %s", ev.Entry.ID)
+	}
+
+	cancelFn()
+	for range eventCh {
+	}
+}